@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-mcp-context/internal/initialize"
+	"go-mcp-context/internal/middleware"
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/internal/transport/stdio"
+	"go-mcp-context/pkg/core"
+	"go-mcp-context/pkg/global"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+// runLocal 直连本地数据库处理 MCP 请求
+// 每条请求在独立 goroutine 中处理，notifications/cancelled 可以取消进行中的请求
+func runLocal(ctx context.Context, in io.Reader, out io.Writer, apiKey string) error {
+	global.Config = core.InitConf()
+	global.Log = core.InitLogger()
+
+	global.DB = initialize.InitGorm()
+
+	initialize.InitBufferedWriters()        // MCP 调用日志、统计
+	defer initialize.CloseBufferedWriters() // 退出时刷新缓冲区
+
+	global.Redis = initialize.ConnectRedis()
+	defer global.Redis.Close()
+
+	global.Cache = initialize.InitCache()
+	global.Embedding = initialize.InitEmbedding()
+	initialize.InitLLM()           // LLM 重排序依赖
+	initialize.InitReranker()      // 检索重排序
+	initialize.InitEventHandlers() // 注册内部事件订阅（MCP 资源变更通知）
+
+	// 调用者身份：提供 API Key 时使用其所属用户，否则为匿名
+	userID := uuid.Nil.String()
+	if apiKey != "" {
+		id, err := service.ServiceGroupApp.ApiKeyService.ValidateAPIKey(apiKey)
+		if err != nil {
+			return fmt.Errorf("invalid API key: %w", err)
+		}
+		userID = id
+	}
+
+	global.Log.Info("MCP stdio 服务启动", zap.String("mode", "local"))
+
+	writer := stdio.NewWriter(out)
+	handler := service.NewMCPHandler()
+
+	// 资源订阅、日志级别与服务端推送挂在连接对应的会话上
+	sessionID, closeSession := openLocalSession(ctx, userID, writer)
+	defer closeSession()
+	var wg sync.WaitGroup
+
+	// stdio 连接即会话：initialize 协商的客户端信息用于之后的所有请求
+	var client atomic.Pointer[transport.ClientInfo]
+
+	handle := func(line []byte) {
+		// JSON-RPC 批量请求：子调用有界并发执行，响应组成数组写为一行
+		if isBatchLine(line) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				serveLocalBatch(ctx, handler, writer, line, userID, sessionID, &client)
+			}()
+			return
+		}
+
+		var body map[string]interface{}
+		var req request.MCPRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			writer.WriteError(&response.MCPError{Code: -32700, Message: "Parse error"}, nil)
+			return
+		}
+		if req.Method == "" {
+			// 客户端对服务端请求（elicitation/create 等）的响应
+			if resp, ok := transport.ParseClientResponse(line); ok {
+				handler.ProcessResponse(&transport.RequestContext{Transport: transport.TransportStdio, UserID: userID, SessionID: sessionID, Ctx: ctx}, resp)
+				return
+			}
+			writer.WriteError(&response.MCPError{Code: -32600, Message: "Invalid Request"}, req.ID)
+			return
+		}
+		_ = json.Unmarshal(line, &body)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveLocal(ctx, handler, &callWriter{Writer: writer}, &req, body, userID, sessionID, &client)
+		}()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- stdio.ReadMessages(in, handle) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+	}
+	wg.Wait()
+	return err
+}
+
+// openLocalSession 为 stdio 连接创建会话，并将推送到会话通道的消息（资源变更、列表变更、日志）写到标准输出
+// 未配置 Redis 时返回空会话，订阅类请求按无会话处理
+func openLocalSession(ctx context.Context, userID string, writer *stdio.Writer) (string, func()) {
+	store := session.GetStore()
+	if store == nil {
+		return "", func() {}
+	}
+
+	sess, err := store.Create(ctx, userID)
+	if err != nil {
+		global.Log.Warn("创建stdio会话失败", zap.Error(err))
+		return "", func() {}
+	}
+
+	pubsub := store.Subscribe(ctx, sess.ID)
+	// 等待订阅确认，确保之后发布的消息不会丢失
+	if _, err := pubsub.Receive(ctx); err != nil {
+		global.Log.Warn("订阅stdio会话通道失败", zap.String("session_id", sess.ID), zap.Error(err))
+		pubsub.Close()
+		store.Delete(context.Background(), sess.ID)
+		return "", func() {}
+	}
+
+	go func() {
+		// 会话按 SessionTTL 空闲过期，连接存活期间定期刷新
+		ticker := time.NewTicker(session.SessionTTL / 2)
+		defer ticker.Stop()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case msg, ok := <-messages:
+				if !ok || session.IsCloseMessage(msg) {
+					return
+				}
+				if err := writer.WriteLine([]byte(msg.Payload)); err != nil {
+					global.Log.Warn("写入stdio推送消息失败", zap.Error(err))
+				}
+			case <-ticker.C:
+				if _, err := store.Validate(ctx, sess.ID, userID); err != nil {
+					global.Log.Warn("刷新stdio会话失败", zap.String("session_id", sess.ID), zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return sess.ID, func() {
+		pubsub.Close()
+		if err := store.Delete(context.Background(), sess.ID); err != nil {
+			global.Log.Warn("删除stdio会话失败", zap.String("session_id", sess.ID), zap.Error(err))
+		}
+	}
+}
+
+// isBatchLine 判断一行消息是否为JSON数组
+func isBatchLine(line []byte) bool {
+	trimmed := bytes.TrimLeft(line, " \t\r")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// serveLocalBatch 处理 JSON-RPC 批量请求，与 HTTP 批量请求相同的数量与并发限制
+// 通知类子调用不产生响应，全部为通知时不写出任何内容
+func serveLocalBatch(ctx context.Context, handler *service.MCPHandler, writer *stdio.Writer,
+	line []byte, userID, sessionID string, client *atomic.Pointer[transport.ClientInfo]) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(line, &entries); err != nil {
+		writer.WriteError(&response.MCPError{Code: -32700, Message: "Parse error"}, nil)
+		return
+	}
+	if len(entries) == 0 || len(entries) > transport.MaxBatchSize {
+		writer.WriteError(&response.MCPError{Code: -32600, Message: "Invalid Request"}, nil)
+		return
+	}
+
+	results := make([]*response.MCPResponse, len(entries))
+	sem := make(chan struct{}, transport.MaxBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, entry json.RawMessage) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = serveLocalBatchEntry(ctx, handler, writer, entry, userID, sessionID, client)
+		}(i, entry)
+	}
+	wg.Wait()
+
+	responses := make([]*response.MCPResponse, 0, len(results))
+	for _, resp := range results {
+		if resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return
+	}
+	if err := writer.WriteMessage(responses); err != nil {
+		global.Log.Error("写入批量响应失败", zap.Error(err))
+	}
+}
+
+// serveLocalBatchEntry 执行批量请求中的单个子调用，返回其响应（通知与客户端响应为nil）
+func serveLocalBatchEntry(ctx context.Context, handler *service.MCPHandler, writer *stdio.Writer,
+	entry json.RawMessage, userID, sessionID string, client *atomic.Pointer[transport.ClientInfo]) *response.MCPResponse {
+	var req request.MCPRequest
+	if err := json.Unmarshal(entry, &req); err != nil || req.Method == "" {
+		// 客户端对服务端请求（elicitation/create 等）的响应
+		if resp, ok := transport.ParseClientResponse(entry); ok {
+			handler.ProcessResponse(&transport.RequestContext{Transport: transport.TransportStdio, UserID: userID, SessionID: sessionID, Ctx: ctx}, resp)
+			return nil
+		}
+		return &response.MCPResponse{JSONRPC: "2.0", ID: nil, Error: &response.MCPError{Code: -32600, Message: "Invalid Request"}}
+	}
+
+	var body map[string]interface{}
+	_ = json.Unmarshal(entry, &body)
+
+	callWriter := &callWriter{Writer: writer, batch: true}
+	serveLocal(ctx, handler, callWriter, &req, body, userID, sessionID, client)
+	if req.ID == nil {
+		return nil
+	}
+	return callWriter.resp
+}
+
+// serveLocal 处理单条请求并记录调用日志
+func serveLocal(ctx context.Context, handler *service.MCPHandler, callWriter *callWriter,
+	req *request.MCPRequest, body map[string]interface{}, userID, sessionID string, client *atomic.Pointer[transport.ClientInfo]) {
+	startTime := time.Now()
+
+	reqCtx := &transport.RequestContext{
+		Transport: transport.TransportStdio,
+		UserID:    userID,
+		SessionID: sessionID,
+		Method:    req.Method,
+		Params:    req.Params,
+		ID:        req.ID,
+		Ctx:       ctx,
+		Client:    client.Load(),
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			global.Log.Error("stdio请求处理panic", zap.String("method", req.Method), zap.Any("panic", r))
+			callWriter.WriteError(&response.MCPError{Code: -32603, Message: "Internal error"}, req.ID)
+		}
+
+		status, errorMsg := "success", ""
+		if callWriter.err != nil {
+			status, errorMsg = "error", callWriter.err.Message
+		}
+		middleware.WriteMCPLog(reqCtx, userID, req.Method, body, int(time.Since(startTime).Milliseconds()), status, errorMsg)
+	}()
+
+	if req.Method == "initialize" {
+		reqCtx.Client = nil
+	}
+	if err := handler.ProcessRequest(reqCtx, callWriter); err != nil {
+		global.Log.Error("处理MCP请求失败", zap.String("method", req.Method), zap.Error(err))
+	}
+	if req.Method == "initialize" && reqCtx.Client != nil {
+		client.Store(reqCtx.Client)
+	}
+}
+
+// callWriter 记录单个请求返回的错误（用于调用日志），写入委托给共享的 stdio 写入器
+// 批量子调用的响应先收集，全部完成后组成数组写出；通知与服务端请求仍直接写出
+type callWriter struct {
+	*stdio.Writer
+	err   *response.MCPError
+	batch bool
+	resp  *response.MCPResponse
+}
+
+// WriteResponse 写入成功响应（批量子调用时收集）
+func (w *callWriter) WriteResponse(resp *response.MCPResponse) error {
+	if w.batch {
+		w.resp = resp
+		return nil
+	}
+	return w.Writer.WriteResponse(resp)
+}
+
+// WriteError 写入错误响应并记录
+func (w *callWriter) WriteError(err *response.MCPError, id interface{}) error {
+	w.err = err
+	if w.batch {
+		w.resp = &response.MCPResponse{JSONRPC: "2.0", ID: id, Error: err}
+		return nil
+	}
+	return w.Writer.WriteError(err, id)
+}
@@ -0,0 +1,59 @@
+// mcp-stdio 以 stdio 子进程方式提供 MCP 服务
+//
+// 供只能通过子进程启动 MCP 服务器的 IDE / CI Agent 使用：从标准输入逐行读取 JSON-RPC 消息，
+// 响应与通知逐行写到标准输出。支持两种模式：
+//
+//	local: 直连本地数据库，复用 MCPHandler.ProcessRequest 处理请求
+//	proxy: 作为轻量代理，将消息转发到远端服务的 /mcp 接口（使用 API Key 认证）
+//
+// 示例：
+//
+//	mcp-stdio -mode local -config /path/to/configs/config.yaml
+//	mcp-stdio -mode proxy -url http://10.21.71.19:8090/mcp -api-key $MCP_API_KEY
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	mode := flag.String("mode", "local", "运行模式：local（直连数据库）或 proxy（转发到远端服务）")
+	configPath := flag.String("config", "", "配置文件路径（local 模式，默认 configs/config.yaml）")
+	endpoint := flag.String("url", "", "远端 MCP 接口地址（proxy 模式），如 http://host:8090/mcp")
+	apiKey := flag.String("api-key", os.Getenv("MCP_API_KEY"), "API Key（默认读取环境变量 MCP_API_KEY）")
+	flag.Parse()
+
+	// 标准输出只用于协议消息，初始化过程中的打印输出重定向到标准错误
+	out := os.Stdout
+	os.Stdout = os.Stderr
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch *mode {
+	case "local":
+		if *configPath != "" {
+			os.Setenv("APP_CONFIG", *configPath)
+		}
+		err = runLocal(ctx, os.Stdin, out, *apiKey)
+	case "proxy":
+		if *endpoint == "" {
+			err = fmt.Errorf("proxy mode requires -url")
+			break
+		}
+		err = runProxy(ctx, os.Stdin, out, *endpoint, *apiKey)
+	default:
+		err = fmt.Errorf("unknown mode: %s", *mode)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mcp-stdio: %v\n", err)
+		os.Exit(1)
+	}
+}
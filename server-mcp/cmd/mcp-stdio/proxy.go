@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport/stdio"
+	"go-mcp-context/internal/transport/streamable"
+)
+
+// proxy 将 stdio 消息转发到远端 Streamable HTTP 接口
+type proxy struct {
+	ctx      context.Context
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	writer   *stdio.Writer
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string // initialize 协商的协议版本，之后的请求通过 MCP-Protocol-Version 头声明
+	streamOnce      sync.Once
+}
+
+// runProxy 以代理模式运行：逐行转发请求，并把响应与服务端推送原样写回标准输出
+func runProxy(ctx context.Context, in io.Reader, out io.Writer, endpoint, apiKey string) error {
+	p := &proxy{
+		ctx:      ctx,
+		client:   &http.Client{},
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		writer:   stdio.NewWriter(out),
+	}
+	defer p.closeSession()
+
+	var wg sync.WaitGroup
+	handle := func(body []byte) {
+		// initialize 需要先拿到会话ID，其余请求才能带上会话头，因此同步转发
+		if requestMethod(body) == "initialize" {
+			p.forward(body)
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.forward(body)
+		}()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- stdio.ReadMessages(in, handle) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+	}
+	wg.Wait()
+	return err
+}
+
+// forward 转发单条消息并写回响应
+func (p *proxy) forward(body []byte) {
+	id := requestID(body)
+
+	req, err := p.newRequest(http.MethodPost, bytes.NewReader(body))
+	if err != nil {
+		p.writeError(id, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		p.writeError(id, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if sessionID := resp.Header.Get(streamable.HeaderSessionID); sessionID != "" {
+		p.setSession(sessionID)
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		return // 通知无响应体
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		p.copyEvents(resp.Body)
+		return
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		p.writeError(id, err)
+		return
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil || !isJSONRPC(compacted.Bytes()) {
+		p.writeError(id, fmt.Errorf("unexpected response: %s", resp.Status))
+		return
+	}
+	p.recordProtocolVersion(compacted.Bytes())
+	p.writer.WriteLine(compacted.Bytes())
+}
+
+// setSession 记录会话ID，首次拿到时打开通知流
+func (p *proxy) setSession(sessionID string) {
+	p.mu.Lock()
+	p.sessionID = sessionID
+	p.mu.Unlock()
+
+	p.streamOnce.Do(func() {
+		go p.openStream()
+	})
+}
+
+// recordProtocolVersion 记录 initialize 响应中协商的协议版本
+func (p *proxy) recordProtocolVersion(data []byte) {
+	var resp struct {
+		Result struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		} `json:"result"`
+	}
+	if json.Unmarshal(data, &resp) != nil || resp.Result.ProtocolVersion == "" {
+		return
+	}
+	p.mu.Lock()
+	p.protocolVersion = resp.Result.ProtocolVersion
+	p.mu.Unlock()
+}
+
+// openStream 打开会话通知流（GET），转发服务端主动推送的通知
+func (p *proxy) openStream() {
+	req, err := p.newRequest(http.MethodGet, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	p.copyEvents(resp.Body)
+}
+
+// closeSession 退出时终止远端会话
+func (p *proxy) closeSession() {
+	p.mu.Lock()
+	sessionID := p.sessionID
+	p.mu.Unlock()
+	if sessionID == "" {
+		return
+	}
+
+	// 进程可能因信号退出，使用独立的 context
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, p.endpoint, nil)
+	if err != nil {
+		return
+	}
+	p.setHeaders(req, sessionID)
+	if resp, err := p.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// newRequest 创建携带认证与会话头的请求
+func (p *proxy) newRequest(method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(p.ctx, method, p.endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	sessionID, protocolVersion := p.sessionID, p.protocolVersion
+	p.mu.Unlock()
+	p.setHeaders(req, sessionID)
+	if protocolVersion != "" {
+		req.Header.Set(streamable.HeaderProtocolVersion, protocolVersion)
+	}
+	return req, nil
+}
+
+func (p *proxy) setHeaders(req *http.Request, sessionID string) {
+	if p.apiKey != "" {
+		req.Header.Set("MCP_API_KEY", p.apiKey)
+	}
+	if sessionID != "" {
+		req.Header.Set(streamable.HeaderSessionID, sessionID)
+	}
+}
+
+// copyEvents 将 SSE 事件的 data 行逐行写到标准输出
+func (p *proxy) copyEvents(body io.Reader) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // event、id、注释等行
+		}
+		data = strings.TrimSpace(data)
+		if data == "" || !isJSONRPC([]byte(data)) {
+			continue
+		}
+		p.writer.WriteLine([]byte(data))
+	}
+}
+
+// writeError 转发失败时返回 JSON-RPC 错误（通知无ID，不返回）
+func (p *proxy) writeError(id interface{}, err error) {
+	if id == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	p.writer.WriteError(&response.MCPError{Code: -32603, Message: err.Error()}, id)
+}
+
+// requestMethod 提取消息的 method（批量或无法解析时返回空）
+func requestMethod(body []byte) string {
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(body, &msg) != nil {
+		return ""
+	}
+	return msg.Method
+}
+
+// requestID 提取消息的 id（通知、批量或无法解析时返回 nil）
+func requestID(body []byte) interface{} {
+	var msg struct {
+		ID interface{} `json:"id"`
+	}
+	if json.Unmarshal(body, &msg) != nil {
+		return nil
+	}
+	return msg.ID
+}
+
+// isJSONRPC 判断是否为 JSON-RPC 消息（对象或批量数组）
+func isJSONRPC(data []byte) bool {
+	if !json.Valid(data) {
+		return false
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return false
+	}
+	if trimmed[0] == '[' {
+		return true
+	}
+	var msg struct {
+		JSONRPC string `json:"jsonrpc"`
+	}
+	return json.Unmarshal(trimmed, &msg) == nil && msg.JSONRPC == "2.0"
+}
@@ -302,9 +302,10 @@ func (d *DocumentApi) GetChunks(c *gin.Context) {
 		}
 
 		response.OkWithData(gin.H{
-			"chunks": chunks,
-			"total":  searchResult.Total,
-			"topic":  topic,
+			"chunks":          chunks,
+			"total":           searchResult.Total,
+			"topic":           topic,
+			"query_expansion": searchResult.QueryExpansion, // 查询扩展情况（未启用时为空）
 		}, c)
 		return
 	}
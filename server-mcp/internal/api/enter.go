@@ -12,6 +12,8 @@ type ApiGroup struct {
 	ApiKeyApi
 	ActivityLogApi
 	StatsApi
+	PromptApi
+	OAuthApi
 }
 
 var ApiGroupApp = new(ApiGroup)
@@ -23,3 +25,5 @@ var mcpService = service.ServiceGroupApp.MCPService
 var apiKeyService = service.ServiceGroupApp.ApiKeyService
 var activityLogService = service.ServiceGroupApp.ActivityLogService
 var statsService = service.ServiceGroupApp.StatsService
+var promptService = service.ServiceGroupApp.PromptService
+var oauthService = service.ServiceGroupApp.OAuthService
@@ -113,7 +113,7 @@ func (l *LibraryApi) Get(c *gin.Context) {
 
 // Update 更新库
 // @Summary 更新库信息
-// @Description 更新库的名称和描述（需要认证）
+// @Description 更新库的名称、描述和库级检索权重 search_weights（需要认证）
 // @Tags Libraries
 // @Accept json
 // @Produce json
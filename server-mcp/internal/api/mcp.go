@@ -1,16 +1,22 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 
 	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/model/response"
 	"go-mcp-context/internal/service"
 	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/internal/transport/sse"
 	"go-mcp-context/internal/transport/streamable"
 	"go-mcp-context/pkg/global"
+	"go-mcp-context/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 )
 
@@ -84,7 +90,7 @@ type MCPApi struct{}
 
 // HandleRequest 处理 MCP JSON-RPC 请求 (新的统一入口)
 // @Summary MCP 请求处理
-// @Description 处理 MCP JSON-RPC 2.0 协议请求，支持 initialize、tools/list、tools/call、resources/list 等方法（需要 MCP_API_KEY）
+// @Description 处理 MCP JSON-RPC 2.0 协议请求，支持 initialize、tools/list、tools/call、resources/list 等方法；请求体为数组时按批量请求处理，返回响应数组（需要 MCP_API_KEY）
 // @Tags MCP
 // @Accept json
 // @Produce json
@@ -95,25 +101,62 @@ type MCPApi struct{}
 // @Failure 401 {object} response.MCPResponse
 // @Router /mcp [post]
 func (m *MCPApi) HandleRequest(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		writeParseError(c)
+		return
+	}
+
+	// initialize 之后的请求应携带协商的协议版本，不支持的版本直接拒绝
+	if version := c.GetHeader(streamable.HeaderProtocolVersion); version != "" && !service.IsSupportedProtocolVersion(version) {
+		writeUnsupportedProtocolVersion(c, version)
+		return
+	}
+
+	// JSON数组为批量请求
+	if isBatchRequest(body) {
+		m.handleBatch(c, body)
+		return
+	}
+
 	// 解析JSON-RPC请求
 	var req request.MCPRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, response.MCPResponse{
-			JSONRPC: "2.0",
-			ID:      nil,
-			Error: &response.MCPError{
-				Code:    -32700,
-				Message: "Parse error",
-			},
-		})
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeParseError(c)
 		return
 	}
 
 	// 1. 检测传输协议
 	transportType := transport.DetectTransport(c)
+	userID := utils.GetUUID(c).String()
+	sessionID, err := resolveSession(c, transportType, userID)
+	if err != nil {
+		writeSessionNotFound(c, req.ID)
+		return
+	}
+
+	// 客户端对服务端请求（elicitation/create 等）的响应：交给等待中的工具调用
+	if req.Method == "" {
+		if resp, ok := transport.ParseClientResponse(body); ok {
+			delivered := service.NewMCPHandler().ProcessResponse(&transport.RequestContext{
+				Transport: transportType,
+				SessionID: sessionID,
+				UserID:    userID,
+				Ctx:       c.Request.Context(),
+			}, resp)
+			if !delivered {
+				writeNoPendingRequest(c, resp.ID)
+				return
+			}
+			c.Status(http.StatusAccepted)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+	}
 
 	// 2. 创建响应写入器
 	writer := transport.CreateResponseWriter(c, transportType)
+	defer writer.Close()
 
 	// 3. 对于Streamable，设置请求信息以判断是否需要流式
 	if streamableWriter, ok := writer.(*streamable.StreamableResponseWriter); ok {
@@ -122,21 +165,221 @@ func (m *MCPApi) HandleRequest(c *gin.Context) {
 
 	// 4. 构造请求上下文
 	reqCtx := &transport.RequestContext{
-		Transport: transportType,
-		Method:    req.Method,
-		Params:    req.Params,
-		ID:        req.ID,
-		GinCtx:    c,
+		Transport:       transportType,
+		SessionID:       sessionID,
+		UserID:          userID,
+		Method:          req.Method,
+		Params:          req.Params,
+		ID:              req.ID,
+		GinCtx:          c,
+		Ctx:             c.Request.Context(),
+		ProtocolVersion: c.GetHeader(streamable.HeaderProtocolVersion),
 	}
 
 	// 5. 调用统一处理器
 	handler := service.NewMCPHandler()
-	err := handler.ProcessRequest(reqCtx, writer)
-	if err != nil {
+	if err := handler.ProcessRequest(reqCtx, writer); err != nil {
 		global.Log.Error("处理MCP请求失败", zap.Error(err))
 	}
 }
 
+// resolveSession 校验请求携带的会话，返回会话ID（无状态请求返回空字符串）
+func resolveSession(c *gin.Context, transportType transport.TransportType, userID string) (string, error) {
+	if transportType == transport.TransportSSE || c.Query("sessionId") != "" {
+		// SSE协议: 校验会话存在且归属当前用户，只能向自己建立的连接推送
+		sessionID := transport.SessionIDFromRequest(c)
+		if err := sse.GetGlobalSSEManager().ValidateSession(c.Request.Context(), sessionID, userID); err != nil {
+			return "", err
+		}
+		return sessionID, nil
+	}
+
+	// Streamable HTTP: 校验 initialize 时下发的会话
+	// 未携带会话头的请求按无状态方式处理，兼容旧客户端
+	sessionID := c.GetHeader(streamable.HeaderSessionID)
+	if sessionID == "" {
+		return "", nil
+	}
+	store := session.GetStore()
+	if store == nil {
+		return "", nil
+	}
+	if _, err := store.Validate(c.Request.Context(), sessionID, userID); err != nil {
+		return "", err
+	}
+	return sessionID, nil
+}
+
+// HandleSSE 打开服务端推送流
+// @Summary MCP SSE 连接
+// @Description 携带 Mcp-Session-Id 头时打开 Streamable HTTP 会话的通知流；否则建立 HTTP+SSE 长连接，首先推送 endpoint 事件（携带 sessionId 的 POST 地址），之后通过 message 事件推送 JSON-RPC 响应（需要 MCP_API_KEY）
+// @Tags MCP
+// @Produce text/event-stream
+// @Security MCP_API_KEY
+// @Param Mcp-Session-Id header string false "Streamable HTTP 会话 ID"
+// @Success 200 {string} string "SSE 事件流"
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.MCPResponse
+// @Failure 409 {object} response.MCPResponse
+// @Router /mcp [get]
+func (m *MCPApi) HandleSSE(c *gin.Context) {
+	if sessionID := c.GetHeader(streamable.HeaderSessionID); sessionID != "" {
+		m.handleSessionStream(c, sessionID)
+		return
+	}
+
+	sessionID := uuid.Must(uuid.NewV4()).String()
+	userID := utils.GetUUID(c).String()
+
+	manager := sse.GetGlobalSSEManager()
+	conn := sse.NewSSEConnection(c, sessionID, userID)
+	manager.Register(conn)
+	defer manager.Unregister(sessionID)
+
+	global.Log.Info("SSE连接已建立",
+		zap.String("session_id", sessionID),
+		zap.String("user_uuid", userID),
+	)
+
+	// 客户端后续向此地址 POST 消息
+	endpoint := c.Request.URL.Path + "?sessionId=" + sessionID
+	if err := conn.Serve(c.Request.Context(), endpoint); err != nil {
+		global.Log.Warn("SSE连接异常断开", zap.String("session_id", sessionID), zap.Error(err))
+		return
+	}
+
+	global.Log.Info("SSE连接已关闭", zap.String("session_id", sessionID))
+}
+
+// handleSessionStream 打开 Streamable HTTP 会话的服务端通知流
+func (m *MCPApi) handleSessionStream(c *gin.Context, sessionID string) {
+	store := session.GetStore()
+	if store == nil {
+		c.Status(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err := store.Validate(c.Request.Context(), sessionID, utils.GetUUID(c).String()); err != nil {
+		writeSessionNotFound(c, nil)
+		return
+	}
+
+	err := streamable.ServeSessionStream(c, store, sessionID)
+	if errors.Is(err, session.ErrStreamExists) {
+		c.JSON(http.StatusConflict, response.MCPResponse{
+			JSONRPC: "2.0",
+			Error: &response.MCPError{
+				Code:    -32000,
+				Message: "Stream already open for this session",
+			},
+		})
+		return
+	}
+	if err != nil {
+		global.Log.Warn("会话通知流异常断开", zap.String("session_id", sessionID), zap.Error(err))
+	}
+}
+
+// HandleDeleteSession 终止 Streamable HTTP 会话
+// @Summary 终止 MCP 会话
+// @Description 终止 Mcp-Session-Id 对应的会话，并关闭其通知流（需要 MCP_API_KEY）
+// @Tags MCP
+// @Security MCP_API_KEY
+// @Param Mcp-Session-Id header string true "Streamable HTTP 会话 ID"
+// @Success 200 {string} string "会话已终止"
+// @Failure 400 {object} response.MCPResponse
+// @Failure 404 {object} response.MCPResponse
+// @Router /mcp [delete]
+func (m *MCPApi) HandleDeleteSession(c *gin.Context) {
+	sessionID := c.GetHeader(streamable.HeaderSessionID)
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, response.MCPResponse{
+			JSONRPC: "2.0",
+			Error: &response.MCPError{
+				Code:    -32600,
+				Message: "Missing " + streamable.HeaderSessionID + " header",
+			},
+		})
+		return
+	}
+
+	store := session.GetStore()
+	if store == nil {
+		c.Status(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := store.Validate(ctx, sessionID, utils.GetUUID(c).String()); err != nil {
+		writeSessionNotFound(c, nil)
+		return
+	}
+	if err := store.Delete(ctx, sessionID); err != nil && !errors.Is(err, session.ErrSessionNotFound) {
+		global.Log.Error("终止MCP会话失败", zap.String("session_id", sessionID), zap.Error(err))
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	global.Log.Info("MCP会话已终止", zap.String("session_id", sessionID))
+	c.Status(http.StatusOK)
+}
+
+// writeParseError 返回JSON解析错误
+func writeParseError(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      nil,
+		Error: &response.MCPError{
+			Code:    -32700,
+			Message: "Parse error",
+		},
+	})
+}
+
+// writeSessionNotFound 返回会话不存在（客户端应重新 initialize）
+func writeSessionNotFound(c *gin.Context, id interface{}) {
+	c.JSON(http.StatusNotFound, response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &response.MCPError{
+			Code:    -32001,
+			Message: "Session not found",
+		},
+	})
+}
+
+// writeNoPendingRequest 返回客户端响应没有对应的服务端请求（已超时或请求不存在）
+func writeNoPendingRequest(c *gin.Context, id interface{}) {
+	c.JSON(http.StatusNotFound, noPendingRequestResponse(id))
+}
+
+// noPendingRequestResponse 客户端响应没有对应服务端请求时的错误响应
+func noPendingRequestResponse(id interface{}) *response.MCPResponse {
+	return &response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &response.MCPError{
+			Code:    -32600,
+			Message: "No pending request for response",
+		},
+	}
+}
+
+// writeUnsupportedProtocolVersion 返回协议版本不受支持
+func writeUnsupportedProtocolVersion(c *gin.Context, version string) {
+	c.JSON(http.StatusBadRequest, response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      nil,
+		Error: &response.MCPError{
+			Code:    -32600,
+			Message: "Unsupported " + streamable.HeaderProtocolVersion + ": " + version,
+			Data: map[string]interface{}{
+				"supported": service.SupportedProtocolVersions,
+			},
+		},
+	})
+}
+
 // MCPToolResult 工具调用结果
 type MCPToolResult struct {
 	Result      interface{} // 成功时的结果
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/streamable"
+	"go-mcp-context/pkg/global"
+	"go-mcp-context/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// batchResult 批量请求中单个子调用的结果
+type batchResult struct {
+	resp      *response.MCPResponse // 通知类请求为nil
+	record    *transport.CallRecord
+	sessionID string // initialize 创建的会话ID
+}
+
+// isBatchRequest 判断请求体是否为JSON数组
+func isBatchRequest(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// handleBatch 处理 JSON-RPC 批量请求
+// 子调用有界并发执行，响应按请求顺序组成数组返回，通知类请求不产生响应
+func (m *MCPApi) handleBatch(c *gin.Context, body []byte) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(body, &entries); err != nil {
+		writeParseError(c)
+		return
+	}
+
+	if len(entries) == 0 || len(entries) > transport.MaxBatchSize {
+		c.JSON(http.StatusBadRequest, response.MCPResponse{
+			JSONRPC: "2.0",
+			ID:      nil,
+			Error: &response.MCPError{
+				Code:    -32600,
+				Message: "Invalid Request",
+			},
+		})
+		return
+	}
+
+	transportType := transport.DetectTransport(c)
+	userID := utils.GetUUID(c).String()
+	sessionID, err := resolveSession(c, transportType, userID)
+	if err != nil {
+		writeSessionNotFound(c, nil)
+		return
+	}
+
+	handler := service.NewMCPHandler()
+	results := make([]batchResult, len(entries))
+	sem := make(chan struct{}, transport.MaxBatchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, entry := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, entry json.RawMessage) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = processBatchEntry(c, handler, entry, transportType, sessionID, userID)
+		}(i, entry)
+	}
+	wg.Wait()
+
+	// 供调用日志中间件逐条记录子调用
+	records := make([]*transport.CallRecord, 0, len(results))
+	responses := make([]*response.MCPResponse, 0, len(results))
+	for _, result := range results {
+		if result.record != nil {
+			records = append(records, result.record)
+		}
+		if result.resp != nil {
+			responses = append(responses, result.resp)
+		}
+		if result.sessionID != "" && transportType == transport.TransportStreamable {
+			c.Header(streamable.HeaderSessionID, result.sessionID)
+		}
+	}
+	c.Set("mcp_batch_calls", records)
+
+	// 全部为通知时没有响应内容
+	if len(responses) == 0 {
+		c.Status(http.StatusAccepted)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	// SSE协议: 响应数组通过会话流推送
+	if transportType == transport.TransportSSE {
+		if err := transport.Push(c.Request.Context(), sessionID, responses); err != nil {
+			global.Log.Error("推送批量响应失败", zap.String("session_id", sessionID), zap.Error(err))
+		}
+		c.Status(http.StatusAccepted)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// processBatchEntry 执行批量请求中的单个子调用
+// 每个子调用使用独立的 gin.Context 副本，避免统计信息互相覆盖
+func processBatchEntry(c *gin.Context, handler *service.MCPHandler, entry json.RawMessage,
+	transportType transport.TransportType, sessionID, userID string) (result batchResult) {
+	// 客户端对服务端请求（elicitation/create 等）的响应：交给等待中的工具调用，不是一次调用
+	if resp, ok := transport.ParseClientResponse(entry); ok {
+		delivered := handler.ProcessResponse(&transport.RequestContext{
+			Transport: transportType,
+			SessionID: sessionID,
+			UserID:    userID,
+			Ctx:       c.Request.Context(),
+		}, resp)
+		if !delivered {
+			result.resp = noPendingRequestResponse(resp.ID)
+		}
+		return result
+	}
+
+	startTime := time.Now()
+	subCtx := c.Copy()
+	record := &transport.CallRecord{Method: "unknown", GinCtx: subCtx}
+	_ = json.Unmarshal(entry, &record.Body)
+
+	result.record = record
+	var req request.MCPRequest
+	defer func() {
+		// 子调用在独立goroutine中执行，panic不会被Gin的Recovery捕获
+		if r := recover(); r != nil {
+			global.Log.Error("批量请求子调用panic", zap.String("method", record.Method), zap.Any("panic", r))
+			record.Error = &response.MCPError{Code: -32603, Message: "Internal error"}
+			// 使用请求ID以便客户端对应到子调用，通知类请求不返回响应
+			result.resp = nil
+			if req.ID != nil {
+				result.resp = &response.MCPResponse{JSONRPC: "2.0", ID: req.ID, Error: record.Error}
+			}
+		}
+		record.Latency = time.Since(startTime)
+	}()
+
+	if err := json.Unmarshal(entry, &req); err != nil || req.Method == "" {
+		record.Error = &response.MCPError{Code: -32600, Message: "Invalid Request"}
+		result.resp = &response.MCPResponse{JSONRPC: "2.0", ID: nil, Error: record.Error}
+		return result
+	}
+	record.Method = req.Method
+
+	reqCtx := &transport.RequestContext{
+		Transport:       transportType,
+		SessionID:       sessionID,
+		UserID:          userID,
+		Method:          req.Method,
+		Params:          req.Params,
+		ID:              req.ID,
+		GinCtx:          subCtx,
+		Ctx:             c.Request.Context(),
+		ProtocolVersion: c.GetHeader(streamable.HeaderProtocolVersion),
+	}
+
+	writer := transport.NewBatchResponseWriter()
+	if err := handler.ProcessRequest(reqCtx, writer); err != nil {
+		global.Log.Error("处理MCP请求失败", zap.String("method", req.Method), zap.Error(err))
+	}
+
+	if resp := writer.Response(); resp != nil {
+		record.Error = resp.Error
+		// 通知类请求（无ID）不返回响应
+		if req.ID != nil {
+			result.resp = resp
+		}
+	}
+	result.sessionID = writer.SessionID()
+	return result
+}
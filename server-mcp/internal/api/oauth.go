@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthApi struct{}
+
+// ProtectedResourceMetadata MCP 端点的 OAuth 受保护资源元数据
+// @Summary OAuth 受保护资源元数据
+// @Description RFC 9728 受保护资源元数据，MCP 客户端据此发现授权服务器（未配置 mcp.oauth.resource 时不注册）
+// @Tags OAuth
+// @Produce json
+// @Success 200 {object} response.OAuthProtectedResourceMetadata
+// @Router /.well-known/oauth-protected-resource [get]
+func (o *OAuthApi) ProtectedResourceMetadata(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, oauthService.ProtectedResourceMetadata())
+}
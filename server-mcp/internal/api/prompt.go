@@ -0,0 +1,186 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromptApi struct{}
+
+// List 获取提示模板列表
+// @Summary 获取提示模板列表
+// @Description 分页获取数据库中的 MCP 提示模板（内置模板不在此列表中，可通过同名模板覆盖或禁用，需要认证）
+// @Tags Prompts
+// @Accept json
+// @Produce json
+// @Security JWTAuth
+// @Param name query string false "模板名称（模糊匹配）"
+// @Param page query int false "页码，默认 1" default(1)
+// @Param page_size query int false "每页数量，默认 10" default(10)
+// @Success 200 {object} response.Response{data=response.PageResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/v1/prompts [get]
+func (p *PromptApi) List(c *gin.Context) {
+	var req request.PromptList
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	result, err := promptService.List(&req)
+	if err != nil {
+		response.FailWithMessage("查询失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithData(result, c)
+}
+
+// Get 获取提示模板详情
+// @Summary 获取提示模板详情
+// @Description 获取指定 MCP 提示模板（需要认证）
+// @Tags Prompts
+// @Accept json
+// @Produce json
+// @Security JWTAuth
+// @Param id path int true "模板 ID"
+// @Success 200 {object} response.Response{data=database.PromptTemplate}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/prompts/:id [get]
+func (p *PromptApi) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的ID", c)
+		return
+	}
+
+	prompt, err := promptService.GetByID(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			response.FailWithMessage("模板不存在", c)
+			return
+		}
+		response.FailWithMessage("查询失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithData(prompt, c)
+}
+
+// Create 创建提示模板
+// @Summary 创建提示模板
+// @Description 创建 MCP 提示模板。模板中 {{参数名}} 由 prompts/get 参数替换，{{docs}} 替换为按 docs_topic 检索到的文档（需要认证）
+// @Tags Prompts
+// @Accept json
+// @Produce json
+// @Security JWTAuth
+// @Param data body request.PromptCreate true "模板信息"
+// @Success 200 {object} response.Response{data=database.PromptTemplate}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /api/v1/prompts [post]
+func (p *PromptApi) Create(c *gin.Context) {
+	var req request.PromptCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	// 设置创建者
+	req.CreatedBy = utils.GetUUID(c).String()
+
+	prompt, err := promptService.Create(&req)
+	if err != nil {
+		if errors.Is(err, service.ErrAlreadyExists) {
+			response.FailWithMessage("模板名称已存在", c)
+			return
+		}
+		response.FailWithMessage("创建失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithData(prompt, c)
+}
+
+// Update 更新提示模板
+// @Summary 更新提示模板
+// @Description 更新 MCP 提示模板内容（名称不可修改，需要认证）
+// @Tags Prompts
+// @Accept json
+// @Produce json
+// @Security JWTAuth
+// @Param id path int true "模板 ID"
+// @Param data body request.PromptUpdate true "更新信息"
+// @Success 200 {object} response.Response{data=database.PromptTemplate}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/prompts/:id [put]
+func (p *PromptApi) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的ID", c)
+		return
+	}
+
+	var req request.PromptUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	prompt, err := promptService.Update(uint(id), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			response.FailWithMessage("模板不存在", c)
+			return
+		}
+		response.FailWithMessage("更新失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithData(prompt, c)
+}
+
+// Delete 删除提示模板
+// @Summary 删除提示模板
+// @Description 删除指定 MCP 提示模板；删除覆盖内置模板的同名模板后恢复内置模板（需要认证）
+// @Tags Prompts
+// @Accept json
+// @Produce json
+// @Security JWTAuth
+// @Param id path int true "模板 ID"
+// @Success 200 {object} response.Response{data=nil}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/prompts/:id [delete]
+func (p *PromptApi) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的ID", c)
+		return
+	}
+
+	if err := promptService.Delete(uint(id)); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			response.FailWithMessage("模板不存在", c)
+			return
+		}
+		response.FailWithMessage("删除失败", c)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
@@ -0,0 +1,78 @@
+package event
+
+import (
+	"fmt"
+	"sync"
+
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// 进程内事件总线
+//
+// 文档导入、刷新、删除等写操作发布事件，MCP 等订阅方据此推送变更通知。
+// 事件异步投递，处理器的耗时和 panic 不影响发布方。
+
+// Topic 事件主题
+type Topic string
+
+const (
+	// TopicDocsChanged 库某个版本的文档内容发生变化（导入、刷新、删除文档/版本）
+	TopicDocsChanged Topic = "library.docs_changed"
+
+	// TopicLibrariesChanged 库集合发生变化（创建、删除、更新库信息）
+	TopicLibrariesChanged Topic = "library.list_changed"
+
+	// TopicToolsChanged MCP 工具集合发生变化
+	TopicToolsChanged Topic = "mcp.tools_changed"
+
+	// TopicPromptsChanged MCP 提示模板发生变化
+	TopicPromptsChanged Topic = "mcp.prompts_changed"
+)
+
+// Event 事件
+type Event struct {
+	Topic     Topic
+	LibraryID uint   // 库相关事件使用
+	Version   string // 为空表示整个库
+}
+
+// Handler 事件处理器
+type Handler func(e Event)
+
+var (
+	mu       sync.RWMutex
+	handlers = make(map[Topic][]Handler)
+)
+
+// Subscribe 订阅事件主题
+func Subscribe(topic Topic, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[topic] = append(handlers[topic], handler)
+}
+
+// Publish 发布事件（异步投递给所有订阅者）
+func Publish(e Event) {
+	mu.RLock()
+	subscribers := handlers[e.Topic]
+	mu.RUnlock()
+
+	for _, handler := range subscribers {
+		go dispatch(handler, e)
+	}
+}
+
+// dispatch 执行单个处理器，捕获 panic
+func dispatch(handler Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil && global.Log != nil {
+			global.Log.Error("事件处理器panic",
+				zap.String("topic", string(e.Topic)),
+				zap.String("panic", fmt.Sprint(r)),
+			)
+		}
+	}()
+	handler(e)
+}
@@ -0,0 +1,10 @@
+package initialize
+
+import (
+	"go-mcp-context/internal/service"
+)
+
+// InitEventHandlers 注册内部事件总线的订阅方
+func InitEventHandlers() {
+	service.RegisterMCPEventHandlers()
+}
@@ -73,6 +73,7 @@ func InitTables() {
 		&dbmodel.Statistics{},
 		&dbmodel.ActivityLog{},
 		&dbmodel.MCPCallLog{},
+		&dbmodel.PromptTemplate{},
 	); err != nil {
 		fmt.Printf("Failed to migrate database: %v\n", err)
 		os.Exit(1)
@@ -80,6 +81,26 @@ func InitTables() {
 
 	// 创建索引
 	createIndexes()
+
+	// 数据迁移
+	migrateMCPCallLogToolNames()
+}
+
+// migrateMCPCallLogToolNames 回填 tools/call 调用日志的工具名
+// 工具名曾直接写入 func_name（search_libraries / get_library_docs / resolve_dependencies），
+// 统一恢复为 tools_call 并把工具名写入 tool_name；更早的 tools_call 日志同样从请求参数回填
+func migrateMCPCallLogToolNames() {
+	migrateSQL := `
+		UPDATE mcp_call_logs
+		SET func_name = 'tools_call', tool_name = LEFT(params->'params'->>'name', 64)
+		WHERE func_name IN ('tools_call', 'search_libraries', 'get_library_docs', 'resolve_dependencies')
+			AND (tool_name IS NULL OR tool_name = '')
+			AND params->>'method' = 'tools/call'
+			AND params->'params'->>'name' IS NOT NULL
+	`
+	if err := global.DB.Exec(migrateSQL).Error; err != nil {
+		fmt.Printf("Warning: Could not backfill tool_name for mcp_call_logs: %v\n", err)
+	}
 }
 
 // createIndexes 创建数据库索引
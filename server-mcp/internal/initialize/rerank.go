@@ -0,0 +1,49 @@
+package initialize
+
+import (
+	"go-mcp-context/pkg/global"
+	"go-mcp-context/pkg/rerank"
+
+	"go.uber.org/zap"
+)
+
+// InitReranker 初始化检索重排序服务（需在 InitLLM 之后调用）
+func InitReranker() {
+	cfg := global.Config.Rerank
+
+	switch cfg.Provider {
+	case "", "none":
+		global.Reranker = rerank.NewNoopReranker()
+		return
+	case "llm":
+		if global.LLM == nil {
+			global.Log.Warn("Rerank provider is llm but LLM service is not configured, reranking disabled")
+			global.Reranker = rerank.NewNoopReranker()
+			return
+		}
+		global.Reranker = rerank.NewLLMReranker(global.LLM)
+	case "http":
+		if cfg.BaseURL == "" {
+			global.Log.Warn("Rerank provider is http but base_url is empty, reranking disabled")
+			global.Reranker = rerank.NewNoopReranker()
+			return
+		}
+		if cfg.Format != "" && cfg.Format != rerank.FormatCohere && cfg.Format != rerank.FormatTEI {
+			global.Log.Warn("Unsupported rerank format, reranking disabled", zap.String("format", cfg.Format))
+			global.Reranker = rerank.NewNoopReranker()
+			return
+		}
+		global.Reranker = rerank.NewHTTPReranker(rerank.HTTPConfig{
+			Endpoint: cfg.BaseURL,
+			APIKey:   cfg.APIKey,
+			Model:    cfg.Model,
+			Format:   cfg.Format,
+		})
+	default:
+		global.Log.Warn("Unsupported rerank provider, reranking disabled", zap.String("provider", cfg.Provider))
+		global.Reranker = rerank.NewNoopReranker()
+		return
+	}
+
+	global.Log.Info("Reranker initialized successfully", zap.String("reranker", global.Reranker.Name()))
+}
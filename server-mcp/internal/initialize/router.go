@@ -6,6 +6,7 @@ import (
 
 	"go-mcp-context/internal/middleware"
 	"go-mcp-context/internal/router"
+	"go-mcp-context/internal/transport/sse"
 	"go-mcp-context/pkg/global"
 
 	"github.com/gin-contrib/sessions"
@@ -53,6 +54,9 @@ func InitRouter() *gin.Engine {
 		routerGroup.InitBaseRouter(publicRouter) // 健康检查等
 	}
 
+	// OAuth 受保护资源元数据（无需认证）- MCP 客户端发现授权服务器
+	routerGroup.InitOAuthRouter(&r.RouterGroup)
+
 	// API v1 公开路由（无需认证）- 查询类接口 + 认证接口
 	v1Public := r.Group("/api/v1")
 	{
@@ -72,12 +76,14 @@ func InitRouter() *gin.Engine {
 		routerGroup.InitDocumentRouter(v1Private) // POST/DELETE 文档
 		routerGroup.InitApiKeyRouter(v1Private)   // API Key 管理（CRUD）
 		routerGroup.InitStatsRouter(v1Private)    // 统计接口
+		routerGroup.InitPromptRouter(v1Private)   // MCP 提示模板管理（CRUD）
 	}
 
-	// MCP routes（需要 API Key 认证）- IDE 调用
+	// MCP routes（需要 API Key 或 OAuth access token 认证）- IDE 调用
+	sse.Register() // 注册 SSE 传输协议（连接管理器 + 响应写入器）
 	mcp := r.Group("")
-	mcp.Use(middleware.APIKeyAuth())
-	mcp.Use(middleware.MCPLogMiddleware()) // 添加MCP日志中间件，放在API Key认证之后
+	mcp.Use(middleware.MCPAuth())
+	mcp.Use(middleware.MCPLogMiddleware()) // 添加MCP日志中间件，放在认证之后
 	{
 		routerGroup.InitMCPRouter(mcp)
 	}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/pkg/global"
+	"go-mcp-context/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth 管理员权限中间件（需在 SSOJWTAuth 之后使用）
+// 只允许 system.admin_uuids 中的用户访问，未配置管理员时拒绝所有请求
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(utils.GetUUID(c).String()) {
+			response.Forbidden("需要管理员权限", c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin 判断用户是否为管理员
+func IsAdmin(userUUID string) bool {
+	if global.Config == nil || userUUID == "" {
+		return false
+	}
+	for _, admin := range global.Config.System.AdminUUIDs {
+		if admin == userUUID {
+			return true
+		}
+	}
+	return false
+}
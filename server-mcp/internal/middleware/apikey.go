@@ -23,26 +23,34 @@ func APIKeyAuth() gin.HandlerFunc {
 			return
 		}
 
-		// 验证 API Key
-		userUUID, err := apiKeyService.ValidateAPIKey(apiKey)
+		userUUID, err := apiKeyUser(apiKey)
 		if err != nil {
-			global.Log.Warn("API Key 验证失败",
-				zap.String("error", err.Error()),
-				zap.String("key_prefix", safeKeyPrefix(apiKey)),
-			)
 			response.NoAuth("无效的 API Key", c)
 			c.Abort()
 			return
 		}
 
 		// 将用户 UUID 存入上下文
-		parsedUUID, _ := uuid.FromString(userUUID)
-		c.Set("user_uuid", parsedUUID)
+		c.Set("user_uuid", userUUID)
 
 		c.Next()
 	}
 }
 
+// apiKeyUser 验证 API Key 并返回所属用户 UUID
+func apiKeyUser(apiKey string) (uuid.UUID, error) {
+	userUUID, err := apiKeyService.ValidateAPIKey(apiKey)
+	if err != nil {
+		global.Log.Warn("API Key 验证失败",
+			zap.String("error", err.Error()),
+			zap.String("key_prefix", safeKeyPrefix(apiKey)),
+		)
+		return uuid.Nil, err
+	}
+	parsedUUID, _ := uuid.FromString(userUUID)
+	return parsedUUID, nil
+}
+
 // safeKeyPrefix 安全地获取 API Key 前缀用于日志
 func safeKeyPrefix(apiKey string) string {
 	if len(apiKey) > 10 {
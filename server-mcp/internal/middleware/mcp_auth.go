@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/pkg/global"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+var oauthService = service.ServiceGroupApp.OAuthService
+
+// MCPAuth MCP 端点认证中间件
+// 支持三种凭证，解析出的 user_uuid 一致：
+//  1. MCP_API_KEY header
+//  2. Authorization: Bearer <API Key>（只支持 Bearer 的客户端）
+//  3. Authorization: Bearer <OAuth access token>（SSO 颁发，audience 必须包含 mcp.oauth.resource；需启用 OAuth）
+//
+// 启用 OAuth 时，认证失败返回 401 和 WWW-Authenticate 质询，客户端据此发现受保护资源元数据并发起授权
+func MCPAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey := c.GetHeader("MCP_API_KEY"); apiKey != "" {
+			userUUID, err := apiKeyUser(apiKey)
+			if err != nil {
+				mcpAuthFail(c, "无效的 API Key", "invalid_token", "invalid API key")
+				return
+			}
+			c.Set("user_uuid", userUUID)
+			c.Next()
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			mcpAuthFail(c, "未提供 API Key 或 access token", "", "")
+			return
+		}
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || strings.TrimSpace(parts[1]) == "" {
+			mcpAuthFail(c, "token 格式错误", "invalid_request", "malformed authorization header")
+			return
+		}
+		token := strings.TrimSpace(parts[1])
+
+		// Bearer 携带的是 API Key
+		if strings.HasPrefix(token, service.APIKeyPrefix) {
+			userUUID, err := apiKeyUser(token)
+			if err != nil {
+				mcpAuthFail(c, "无效的 API Key", "invalid_token", "invalid API key")
+				return
+			}
+			c.Set("user_uuid", userUUID)
+			c.Next()
+			return
+		}
+
+		if !oauthService.Enabled() {
+			mcpAuthFail(c, "未启用 OAuth，请使用 API Key", "invalid_token", "oauth is not enabled")
+			return
+		}
+		userUUID, err := oauthAccessTokenUser(token)
+		if err != nil {
+			global.Log.Warn("MCP access token 验证失败", zap.Error(err))
+			mcpAuthFail(c, "token 无效: "+err.Error(), "invalid_token", oauthErrorDescription(err))
+			return
+		}
+		c.Set("user_uuid", userUUID)
+		c.Next()
+	}
+}
+
+var errTokenAudience = errors.New("token audience mismatch")
+
+// oauthAccessTokenUser 校验 SSO 颁发的 access token（签名、有效期同 SSOJWTAuth，应用校验改为 audience 校验）
+// MCP 客户端自行用 refresh token 续期，这里不做自动刷新
+func oauthAccessTokenUser(token string) (uuid.UUID, error) {
+	claims, err := ParseSSOAccessToken(token)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if claims.TokenType != "" && claims.TokenType != "access" {
+		return uuid.Nil, TokenInvalid
+	}
+	if !oauthService.AudienceAllowed(claims.Audience) {
+		return uuid.Nil, errTokenAudience
+	}
+	if claims.UserUUID == uuid.Nil {
+		return uuid.Nil, TokenInvalid
+	}
+	return claims.UserUUID, nil
+}
+
+// oauthErrorDescription WWW-Authenticate 的 error_description（RFC 6750 要求 ASCII）
+func oauthErrorDescription(err error) string {
+	switch {
+	case errors.Is(err, TokenExpired):
+		return "token expired"
+	case errors.Is(err, TokenNotValidYet):
+		return "token not active yet"
+	case errors.Is(err, errTokenAudience):
+		return "token audience mismatch"
+	default:
+		return "invalid token"
+	}
+}
+
+// mcpAuthFail 认证失败：启用 OAuth 时返回 401 + WWW-Authenticate 质询，否则保持 API Key 认证的原有响应
+func mcpAuthFail(c *gin.Context, message, errCode, errDescription string) {
+	defer c.Abort()
+	if !oauthService.Enabled() {
+		response.NoAuth(message, c)
+		return
+	}
+
+	challenge := fmt.Sprintf(`Bearer resource_metadata="%s"`, oauthService.ResourceMetadataURL())
+	if scopes := global.Config.MCP.OAuth.ScopesSupported; len(scopes) > 0 {
+		challenge += fmt.Sprintf(`, scope="%s"`, strings.Join(scopes, " "))
+	}
+	if errCode != "" {
+		challenge += fmt.Sprintf(`, error="%s", error_description="%s"`, errCode, errDescription)
+	}
+	c.Header("WWW-Authenticate", challenge)
+	response.Unauthorized(message, c)
+}
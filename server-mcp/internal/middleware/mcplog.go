@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"net/http"
 	"time"
 
 	"go-mcp-context/internal/model/database"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
 	"go-mcp-context/pkg/bufferedwriter/mcplog"
 	"go-mcp-context/pkg/utils"
 
@@ -18,19 +21,24 @@ var methodToFuncName = map[string]string{
 	"initialize":                database.MCPFuncInitialize,
 	"notifications/initialized": database.MCPFuncInitialized,
 	"initialized":               database.MCPFuncInitialized,
+	"notifications/cancelled":   database.MCPFuncCancelled,
 	"tools/list":                database.MCPFuncToolsList,
 	"tools/call":                database.MCPFuncToolsCall,
 	"resources/list":            database.MCPFuncResourcesList,
 	"resources/templates/list":  database.MCPFuncResourceTemplatesList,
-	"search-libraries":          database.MCPFuncSearchLibraries,
-	"get-library-docs":          database.MCPFuncGetLibraryDocs,
+	"resources/subscribe":       database.MCPFuncResourcesSubscribe,
+	"resources/unsubscribe":     database.MCPFuncResourcesUnsubscribe,
+	"prompts/list":              database.MCPFuncPromptsList,
+	"prompts/get":               database.MCPFuncPromptsGet,
+	"logging/setLevel":          database.MCPFuncLoggingSetLevel,
+	"completion/complete":       database.MCPFuncCompletionComplete,
 }
 
 // MCPLogMiddleware MCP调用日志中间件
 func MCPLogMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 只对MCP相关路径记录日志
-		if !isMCPPath(c.Request.URL.Path) {
+		// 只对MCP相关路径的POST请求记录日志（GET为SSE长连接，不属于单次调用）
+		if !isMCPPath(c.Request.URL.Path) || c.Request.Method != http.MethodPost {
 			c.Next()
 			return
 		}
@@ -46,10 +54,19 @@ func MCPLogMiddleware() gin.HandlerFunc {
 			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 		}
 
-		// 解析请求体获取method
+		// 客户端对服务端请求（elicitation/create 等）的响应不是一次调用，不记录
+		if _, ok := transport.ParseClientResponse(bodyBytes); ok {
+			c.Next()
+			return
+		}
+
+		// 解析请求体获取method（批量请求由API层提供子调用记录）
 		var reqBody map[string]interface{}
 		method := "unknown"
-		if len(bodyBytes) > 0 {
+		trimmed := bytes.TrimSpace(bodyBytes)
+		if len(trimmed) > 0 && trimmed[0] == '[' {
+			method = "batch"
+		} else if len(bodyBytes) > 0 {
 			if err := json.Unmarshal(bodyBytes, &reqBody); err == nil {
 				if m, ok := reqBody["method"].(string); ok {
 					method = m
@@ -68,6 +85,14 @@ func MCPLogMiddleware() gin.HandlerFunc {
 		// 执行请求
 		c.Next()
 
+		// 批量请求: 每个子调用单独记录
+		if calls, exists := c.Get("mcp_batch_calls"); exists {
+			if records, ok := calls.([]*transport.CallRecord); ok {
+				logMCPBatchCalls(actorID, records)
+				return
+			}
+		}
+
 		// 统一记录日志
 		logMCPCall(c, startTime, actorID, method, reqBody)
 	}
@@ -102,6 +127,31 @@ func logMCPCall(c *gin.Context, startTime time.Time, actorID, method string, req
 		}
 	}
 
+	WriteMCPLog(c, actorID, method, reqBody, latencyMs, status, errorMsg)
+}
+
+// logMCPBatchCalls 逐条记录批量请求的子调用日志
+func logMCPBatchCalls(actorID string, records []*transport.CallRecord) {
+	for _, record := range records {
+		status := "success"
+		errorMsg := ""
+		if record.Error != nil {
+			status = "error"
+			errorMsg = record.Error.Message
+		}
+
+		WriteMCPLog(record.GinCtx, actorID, record.Method, record.Body, int(record.Latency.Milliseconds()), status, errorMsg)
+	}
+}
+
+// MCPLogValues 调用日志所需统计信息的来源（*gin.Context 或 *transport.RequestContext）
+type MCPLogValues interface {
+	Get(key string) (interface{}, bool)
+}
+
+// WriteMCPLog 写入一条MCP调用日志（结果数量、库ID从上下文读取）
+// stdio 等不经过中间件的传输协议直接调用
+func WriteMCPLog(c MCPLogValues, actorID, method string, reqBody map[string]interface{}, latencyMs int, status, errorMsg string) {
 	// 获取结果数量（如果有的话）
 	resultCount := 0
 	if result, exists := c.Get("mcp_result_count"); exists {
@@ -118,16 +168,42 @@ func logMCPCall(c *gin.Context, startTime time.Time, actorID, method string, req
 		}
 	}
 
+	// 工具执行失败（isError 结果）同样记录为失败调用
+	if toolErr, exists := c.Get("mcp_tool_error"); exists {
+		if msg, ok := toolErr.(string); ok {
+			status = "error"
+			errorMsg = msg
+		}
+	}
+
+	// 客户端信息（initialize 协商后记录在会话中）
+	var client transport.ClientInfo
+	if value, exists := c.Get("mcp_client"); exists {
+		if info, ok := value.(*transport.ClientInfo); ok && info != nil {
+			client = *info
+		}
+	}
+
 	// 获取正确的funcName
 	funcName := method // 默认使用method
 	if mappedName, exists := methodToFuncName[method]; exists {
 		funcName = mappedName
 	}
+	// 工具调用的函数名保持 tools_call，工具名单独记录（只记录注册表中的工具，包括已关闭的）
+	var toolName string
+	if method == "tools/call" {
+		if params, ok := reqBody["params"].(map[string]interface{}); ok {
+			if name, ok := params["name"].(string); ok && service.MCPTools.Has(name) {
+				toolName = name
+			}
+		}
+	}
 
 	// 记录日志
 	logEntry := &mcplog.LogEntry{
 		ActorID:     actorID,
 		FuncName:    funcName,
+		ToolName:    toolName,
 		LibraryID:   libraryID,
 		Params:      reqBody, // 整个请求体存到params
 		ResultCount: resultCount,
@@ -135,6 +211,10 @@ func logMCPCall(c *gin.Context, startTime time.Time, actorID, method string, req
 		Status:      status,
 		ErrorMsg:    errorMsg,
 		CreatedAt:   time.Now(),
+
+		ClientName:      client.Name,
+		ClientVersion:   client.Version,
+		ProtocolVersion: client.ProtocolVersion,
 	}
 
 	mcplog.Log(logEntry)
@@ -1,6 +1,10 @@
 package database
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
 	"go-mcp-context/pkg/global"
 
 	"github.com/lib/pq"
@@ -18,9 +22,10 @@ type Library struct {
 	SourceType     string          `json:"source_type" gorm:"size:20;default:'local'"` // github, website, local
 	SourceURL      string          `json:"source_url" gorm:"size:500"`                 // vuejs/docs 或 vuejs.org/guide
 	EmbeddingModel string          `json:"embedding_model" gorm:"size:100;default:'text-embedding-3-small'"`
-	Embedding      pgvector.Vector `json:"-" gorm:"type:vector(1536);default:null"` // 库名+描述的向量表示（用于语义搜索）
-	Status         string          `json:"status" gorm:"size:20;default:'active'"`  // active, archived, deleted
-	CreatedBy      string          `json:"created_by" gorm:"size:36;index"`         // 创建者 UUID，空值表示公共库
+	Embedding      pgvector.Vector `json:"-" gorm:"type:vector(1536);default:null"`    // 库名+描述的向量表示（用于语义搜索）
+	Status         string          `json:"status" gorm:"size:20;default:'active'"`     // active, archived, deleted
+	CreatedBy      string          `json:"created_by" gorm:"size:36;index"`            // 创建者 UUID，空值表示公共库
+	SearchWeights  *SearchWeights  `json:"search_weights,omitempty" gorm:"type:jsonb"` // 库级检索权重（覆盖全局 search 配置，空值使用全局配置）
 	// 关联
 	Uploads []DocumentUpload `json:"uploads,omitempty" gorm:"foreignKey:LibraryID"`
 	Chunks  []DocumentChunk  `json:"chunks,omitempty" gorm:"foreignKey:LibraryID"`
@@ -29,3 +34,37 @@ type Library struct {
 func (Library) TableName() string {
 	return "libraries"
 }
+
+// SearchWeights 库级混合检索权重（jsonb 存储），未设置的字段使用全局 search 配置
+// 如 API 参考类库可提高 bm25_weight，概念指南类库可提高 vector_weight
+type SearchWeights struct {
+	VectorWeight *float64 `json:"vector_weight,omitempty"` // 向量检索 RRF 权重
+	BM25Weight   *float64 `json:"bm25_weight,omitempty"`   // BM25 检索 RRF 权重
+	HotWeight    *float64 `json:"hot_weight,omitempty"`    // 热度权重
+	RRFConstant  *int     `json:"rrf_constant,omitempty"`  // RRF 常量 k
+	VectorTopK   *int     `json:"vector_top_k,omitempty"`  // 向量检索候选数
+	BM25TopK     *int     `json:"bm25_top_k,omitempty"`    // BM25 检索候选数
+}
+
+// IsEmpty 是否未设置任何字段
+func (w *SearchWeights) IsEmpty() bool {
+	return w == nil || (w.VectorWeight == nil && w.BM25Weight == nil && w.HotWeight == nil &&
+		w.RRFConstant == nil && w.VectorTopK == nil && w.BM25TopK == nil)
+}
+
+func (w SearchWeights) Value() (driver.Value, error) {
+	return json.Marshal(w)
+}
+
+func (w *SearchWeights) Scan(value interface{}) error {
+	// 先重置，避免复用的接收者残留上一行的字段
+	*w = SearchWeights{}
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, w)
+}
@@ -10,18 +10,26 @@ const (
 	MCPFuncGetLibraryDocs        = "get_library_docs"
 	MCPFuncInitialize            = "initialize"
 	MCPFuncInitialized           = "initialized"
+	MCPFuncCancelled             = "cancelled"
 	MCPFuncToolsList             = "tools_list"
 	MCPFuncToolsCall             = "tools_call"
 	MCPFuncResourcesList         = "resources_list"
 	MCPFuncResourceTemplatesList = "resource_templates_list"
+	MCPFuncResourcesSubscribe    = "resources_subscribe"
+	MCPFuncResourcesUnsubscribe  = "resources_unsubscribe"
+	MCPFuncPromptsList           = "prompts_list"
+	MCPFuncPromptsGet            = "prompts_get"
+	MCPFuncLoggingSetLevel       = "logging_set_level"
+	MCPFuncCompletionComplete    = "completion_complete"
 )
 
 // MCPCallLog MCP 调用日志
 type MCPCallLog struct {
 	ID        uint   `json:"id" gorm:"primaryKey"`
-	ActorID   string `json:"actor_id" gorm:"size:36;not null;index"`  // 调用者 UUID
-	FuncName  string `json:"func_name" gorm:"size:64;not null;index"` // search_libraries / get_library_docs
-	LibraryID *uint  `json:"library_id,omitempty" gorm:"index"`       // 关联库（get_library_docs 有）
+	ActorID   string `json:"actor_id" gorm:"size:36;not null;index"`   // 调用者 UUID
+	FuncName  string `json:"func_name" gorm:"size:64;not null;index"`  // tools_call / resources_list 等（按 MCP 方法记录）
+	ToolName  string `json:"tool_name,omitempty" gorm:"size:64;index"` // tools/call 调用的工具名（如 get-library-docs）
+	LibraryID *uint  `json:"library_id,omitempty" gorm:"index"`        // 关联库（get-library-docs 有）
 
 	// 请求参数（JSON 格式）
 	Params string `json:"params,omitempty" gorm:"type:jsonb"` // 请求参数 JSON
@@ -32,6 +40,11 @@ type MCPCallLog struct {
 	Status      string `json:"status" gorm:"size:16;default:'success'"` // success/error
 	ErrorMsg    string `json:"error_msg,omitempty" gorm:"size:500"`     // 错误信息
 
+	// 客户端信息（initialize 时声明的 clientInfo 与协商的协议版本）
+	ClientName      string `json:"client_name,omitempty" gorm:"size:128;index"`     // clientInfo.name
+	ClientVersion   string `json:"client_version,omitempty" gorm:"size:64"`         // clientInfo.version
+	ProtocolVersion string `json:"protocol_version,omitempty" gorm:"size:16;index"` // 协商的协议版本
+
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
 }
 
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"go-mcp-context/pkg/global"
+)
+
+// PromptTemplate MCP 提示模板（prompts/list、prompts/get）
+// 模板中的 {{参数名}} 由调用参数替换，{{docs}} 替换为检索到的文档片段
+type PromptTemplate struct {
+	global.MODEL
+	Name        string          `json:"name" gorm:"size:100;not null;uniqueIndex"` // 提示名（prompts/get 使用），唯一
+	Title       string          `json:"title" gorm:"size:255"`                     // 展示名称
+	Description string          `json:"description" gorm:"type:text"`              // 描述
+	Arguments   PromptArguments `json:"arguments" gorm:"type:jsonb"`               // 参数定义
+	Template    string          `json:"template" gorm:"type:text;not null"`        // 消息模板
+	DocsTopic   string          `json:"docs_topic" gorm:"size:500"`                // 检索文档的 topic 模板，空表示不检索
+	DocsVersion string          `json:"docs_version" gorm:"size:100"`              // 检索文档的版本模板，空表示使用 {{version}}
+	DocsMode    string          `json:"docs_mode" gorm:"size:20"`                  // 检索模式：code, info，空表示全部
+	Enabled     bool            `json:"enabled" gorm:"default:true"`               // 是否在 prompts/list 中展示
+	CreatedBy   string          `json:"created_by" gorm:"size:36;index"`           // 创建者 UUID
+}
+
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
+
+// PromptArgument 提示参数定义
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptArguments 提示参数列表（jsonb 存储）
+type PromptArguments []PromptArgument
+
+func (a PromptArguments) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	return json.Marshal(a)
+}
+
+func (a *PromptArguments) Scan(value interface{}) error {
+	if value == nil {
+		*a = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, a)
+}
@@ -6,8 +6,9 @@ import (
 
 // Metric 名称常量
 const (
-	MetricMCPGetLibraryDocs  = "mcp.func.get_library_docs"
-	MetricMCPSearchLibraries = "mcp.func.search_libraries"
+	MetricMCPGetLibraryDocs      = "mcp.func.get_library_docs"
+	MetricMCPSearchLibraries     = "mcp.func.search_libraries"
+	MetricMCPResolveDependencies = "mcp.func.resolve_dependencies"
 )
 
 // Statistics 系统统计
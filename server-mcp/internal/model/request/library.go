@@ -1,5 +1,7 @@
 package request
 
+import dbmodel "go-mcp-context/internal/model/database"
+
 // LibraryCreate 创建库请求（Local 类型）
 type LibraryCreate struct {
 	Name        string `json:"name" binding:"required"`
@@ -9,8 +11,9 @@ type LibraryCreate struct {
 
 // LibraryUpdate 更新库请求（ID 从 URL 路径获取）
 type LibraryUpdate struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name          string                 `json:"name" binding:"required"`
+	Description   string                 `json:"description"`
+	SearchWeights *dbmodel.SearchWeights `json:"search_weights"` // 库级检索权重（不传保持不变，传 {} 恢复全局配置）
 }
 
 // LibraryList 库列表请求
@@ -1,5 +1,7 @@
 package request
 
+import "encoding/json"
+
 // MCPRequest JSON-RPC 2.0 请求
 type MCPRequest struct {
 	JSONRPC string                 `json:"jsonrpc"`
@@ -15,9 +17,58 @@ type MCPSearchLibraries struct {
 
 // MCPGetLibraryDocs get-library-docs 工具参数
 type MCPGetLibraryDocs struct {
-	LibraryID uint   `json:"libraryId"` // 库的数据库 ID
-	Version   string `json:"version"`   // 版本（可选，默认使用 defaultVersion）
-	Topic     string `json:"topic"`
-	Mode      string `json:"mode"` // code, info
-	Page      int    `json:"page"` // 1-10
+	LibraryID  uint   `json:"libraryId"`  // 库的数据库 ID（为 0 时跨库检索）
+	LibraryIDs []uint `json:"libraryIds"` // 跨库检索的库范围（为空时检索所有库）
+	Version    string `json:"version"`    // 版本（可选，默认使用 defaultVersion）
+	Topic      string `json:"topic"`
+	Mode       string `json:"mode"`   // code, info
+	Page       int    `json:"page"`   // 1-10
+	Tokens     int    `json:"tokens"` // 每页 token 预算（可选，不指定时每页固定 10 条）
+}
+
+// MCPResolveDependencies resolve-dependencies 工具参数
+type MCPResolveDependencies struct {
+	Manifests []MCPManifest `json:"manifests"`
+}
+
+// MCPManifest 项目依赖清单文件
+type MCPManifest struct {
+	Filename string `json:"filename"` // 文件名（go.mod、package.json、requirements.txt、pom.xml），用于识别格式
+	Content  string `json:"content"`  // 文件内容
+}
+
+// MCPCompletionRef completion/complete 的补全对象引用
+type MCPCompletionRef struct {
+	Type string `json:"type"`           // ref/prompt 或 ref/resource
+	Name string `json:"name,omitempty"` // 提示名（ref/prompt）
+	URI  string `json:"uri,omitempty"`  // 资源模板URI（ref/resource）
+}
+
+// MCPComplete completion/complete 参数
+type MCPComplete struct {
+	Ref           MCPCompletionRef  `json:"ref"`
+	ArgumentName  string            `json:"argumentName"`  // 待补全的参数名
+	ArgumentValue string            `json:"argumentValue"` // 参数当前已输入的值
+	Arguments     map[string]string `json:"arguments"`     // 已填写的其他参数（context.arguments）
+}
+
+// ProgressToken 提取请求参数中的 _meta.progressToken（字符串或整数，未携带时返回 nil）
+func ProgressToken(params map[string]interface{}) interface{} {
+	meta, ok := params["_meta"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	switch token := meta["progressToken"].(type) {
+	case string, float64, json.Number:
+		return token
+	default:
+		return nil
+	}
+}
+
+// MCPListResources resources/list 参数
+type MCPListResources struct {
+	Cursor string // 上一页返回的 nextCursor（为空表示第一页）
+	Access string // 访问过滤扩展：空（全部）、accessible（公共库与自己的库）、owned（仅自己的库）
+	UserID string // 调用者UUID（访问过滤与游标绑定）
 }
@@ -0,0 +1,35 @@
+package request
+
+import dbmodel "go-mcp-context/internal/model/database"
+
+// PromptCreate 创建提示模板请求
+type PromptCreate struct {
+	Name        string                   `json:"name" binding:"required,max=100"`
+	Title       string                   `json:"title" binding:"max=255"`
+	Description string                   `json:"description"`
+	Arguments   []dbmodel.PromptArgument `json:"arguments"`
+	Template    string                   `json:"template" binding:"required"`
+	DocsTopic   string                   `json:"docs_topic" binding:"max=500"`
+	DocsVersion string                   `json:"docs_version" binding:"max=100"`
+	DocsMode    string                   `json:"docs_mode" binding:"omitempty,oneof=code info"`
+	Enabled     *bool                    `json:"enabled"` // 默认启用
+	CreatedBy   string                   `json:"-"`       // 创建者 UUID（从 JWT 获取，不从请求体读取）
+}
+
+// PromptUpdate 更新提示模板请求（ID 从 URL 路径获取）
+type PromptUpdate struct {
+	Title       string                   `json:"title" binding:"max=255"`
+	Description string                   `json:"description"`
+	Arguments   []dbmodel.PromptArgument `json:"arguments"`
+	Template    string                   `json:"template" binding:"required"`
+	DocsTopic   string                   `json:"docs_topic" binding:"max=500"`
+	DocsVersion string                   `json:"docs_version" binding:"max=100"`
+	DocsMode    string                   `json:"docs_mode" binding:"omitempty,oneof=code info"`
+	Enabled     *bool                    `json:"enabled"`
+}
+
+// PromptList 提示模板列表请求
+type PromptList struct {
+	Name *string `json:"name" form:"name"`
+	PageInfo
+}
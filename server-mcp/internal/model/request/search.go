@@ -1,5 +1,7 @@
 package request
 
+import dbmodel "go-mcp-context/internal/model/database"
+
 // Search 搜索请求
 type Search struct {
 	LibraryID uint   `json:"library_id" binding:"required"`
@@ -8,4 +10,16 @@ type Search struct {
 	Version   string `json:"version" binding:"required"` // 版本，必填
 	Page      int    `json:"page"`                       // 页码，默认 1
 	Limit     int    `json:"limit"`                      // 每页数量，默认 10，最大 50
+
+	TokenBudget int `json:"token_budget"` // 每页 token 预算（大于 0 时按预算装填每页，忽略 Limit）
+
+	Scopes []SearchScope `json:"-"` // 跨库检索的库与版本（非空时忽略 LibraryID、Version）
+
+	Library *dbmodel.Library `json:"-"` // 调用方已加载的库（提供时直接使用其 search_weights，不再查询）
+}
+
+// SearchScope 跨库检索中的单个库版本
+type SearchScope struct {
+	LibraryID uint
+	Version   string
 }
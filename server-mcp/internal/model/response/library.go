@@ -1,6 +1,10 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	dbmodel "go-mcp-context/internal/model/database"
+)
 
 // LibraryListItem 库列表项（前端主页表格，精简字段）
 type LibraryListItem struct {
@@ -16,19 +20,20 @@ type LibraryListItem struct {
 
 // LibraryInfo 库详情响应（完整信息）
 type LibraryInfo struct {
-	ID             uint      `json:"id"`
-	Name           string    `json:"name"`
-	DefaultVersion string    `json:"default_version"`
-	Versions       []string  `json:"versions"`
-	SourceType     string    `json:"source_type"`
-	SourceURL      string    `json:"source_url"`
-	Description    string    `json:"description"`
-	DocumentCount  int       `json:"document_count"`
-	ChunkCount     int       `json:"chunk_count"`
-	TokenCount     int       `json:"token_count"`
-	Status         string    `json:"status"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uint                   `json:"id"`
+	Name           string                 `json:"name"`
+	DefaultVersion string                 `json:"default_version"`
+	Versions       []string               `json:"versions"`
+	SourceType     string                 `json:"source_type"`
+	SourceURL      string                 `json:"source_url"`
+	Description    string                 `json:"description"`
+	DocumentCount  int                    `json:"document_count"`
+	ChunkCount     int                    `json:"chunk_count"`
+	TokenCount     int                    `json:"token_count"`
+	Status         string                 `json:"status"`
+	SearchWeights  *dbmodel.SearchWeights `json:"search_weights,omitempty"` // 库级检索权重（空表示使用全局配置）
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
 }
 
 // VersionInfo 版本信息（用于上传时选择）
@@ -15,6 +15,40 @@ type MCPError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// MCPNotification JSON-RPC 2.0 通知（服务端推送，无ID、无需响应）
+type MCPNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// NewMCPNotification 创建通知
+func NewMCPNotification(method string, params interface{}) *MCPNotification {
+	return &MCPNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+}
+
+// MCPServerRequest JSON-RPC 2.0 服务端请求（服务端发起、需要客户端响应，如 elicitation/create）
+type MCPServerRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// NewMCPServerRequest 创建服务端请求
+func NewMCPServerRequest(id interface{}, method string, params interface{}) *MCPServerRequest {
+	return &MCPServerRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	}
+}
+
 // MCPToolDefinition MCP 工具定义
 type MCPToolDefinition struct {
 	Name        string                 `json:"name"`
@@ -40,14 +74,21 @@ type MCPLibraryInfo struct {
 
 // MCPGetLibraryDocsResult get-library-docs 结果
 type MCPGetLibraryDocsResult struct {
-	LibraryID uint               `json:"libraryId"` // 库的数据库 ID
-	Documents []MCPDocumentChunk `json:"documents"`
-	Page      int                `json:"page"`
-	HasMore   bool               `json:"hasMore"`
+	LibraryID        uint               `json:"libraryId"`                  // 库的数据库 ID（跨库检索时为 0）
+	Version          string             `json:"version,omitempty"`          // 实际检索的版本（跨库检索时见各文档的 version）
+	RequestedVersion string             `json:"requestedVersion,omitempty"` // 请求的版本（与实际版本不同时返回，如空、别名或范围）
+	Documents        []MCPDocumentChunk `json:"documents"`
+	Page             int                `json:"page"`
+	HasMore          bool               `json:"hasMore"`
+	TokenBudget      int                `json:"tokenBudget,omitempty"`    // 请求的 token 预算
+	TokensUsed       int                `json:"tokensUsed"`               // 本页文档的 token 总数
+	QueryExpansion   []QueryExpansion   `json:"queryExpansion,omitempty"` // 各 topic 的查询扩展情况（未启用查询扩展时为空）
 }
 
 // MCPDocumentChunk 文档片段
 type MCPDocumentChunk struct {
+	LibraryID   uint    `json:"libraryId"`             // 所属库 ID
+	Library     string  `json:"library"`               // 所属库名
 	Title       string  `json:"title"`                 // 标题（code mode: LLM 生成, info mode: headers 层级）
 	Description string  `json:"description,omitempty"` // LLM 生成的描述（仅 code mode）
 	Source      string  `json:"source"`                // 来源文件路径
@@ -56,6 +97,73 @@ type MCPDocumentChunk struct {
 	Language    string  `json:"language,omitempty"`    // 代码语言（仅 code mode）
 	Code        string  `json:"code,omitempty"`        // 代码内容（仅 code mode）
 	Content     string  `json:"content,omitempty"`     // ChunkText 原文（仅 info mode）
-	Tokens      int     `json:"tokens"`                // token 数
+	Tokens      int     `json:"tokens"`                // token 数（截断时为截断后的 token 数）
+	Truncated   bool    `json:"truncated,omitempty"`   // 是否因超出 token 预算被截断
 	Relevance   float64 `json:"relevance"`             // 相关性分数 0-1
 }
+
+// MCPResolveDependenciesResult resolve-dependencies 结果
+type MCPResolveDependenciesResult struct {
+	Dependencies []MCPResolvedDependency `json:"dependencies"`
+	Matched      int                     `json:"matched"` // 匹配到库的依赖数
+}
+
+// MCPResolvedDependency 依赖的匹配结果（未匹配到库时只有依赖信息）
+type MCPResolvedDependency struct {
+	Name         string  `json:"name"`                   // 清单中的依赖名
+	Ecosystem    string  `json:"ecosystem"`              // go、npm、pypi、maven
+	Manifest     string  `json:"manifest"`               // 来源清单文件名
+	Requested    string  `json:"requested,omitempty"`    // 清单中的版本或版本约束
+	Dev          bool    `json:"dev,omitempty"`          // 仅开发/测试依赖
+	LibraryID    uint    `json:"libraryId,omitempty"`    // 匹配的库 ID
+	Library      string  `json:"library,omitempty"`      // 匹配的库名
+	Version      string  `json:"version,omitempty"`      // 最接近的已索引版本
+	VersionMatch string  `json:"versionMatch,omitempty"` // 版本匹配方式：exact、range、closest、default
+	MatchedBy    string  `json:"matchedBy,omitempty"`    // 库匹配方式：source_url、alias、name
+	Score        float64 `json:"score,omitempty"`        // 库匹配分数 0-1
+}
+
+// MCPCompleteResult completion/complete 结果
+type MCPCompleteResult struct {
+	Completion MCPCompletion `json:"completion"`
+}
+
+// MCPCompletion 补全候选
+type MCPCompletion struct {
+	Values  []string `json:"values"`  // 候选值（最多100个）
+	Total   int      `json:"total"`   // 候选总数
+	HasMore bool     `json:"hasMore"` // 是否还有更多候选
+}
+
+// MCPPrompt prompts/list 中的提示定义
+type MCPPrompt struct {
+	Name        string              `json:"name"`
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+// MCPPromptArgument 提示参数
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPPromptResult prompts/get 结果
+type MCPPromptResult struct {
+	Description string             `json:"description,omitempty"`
+	Messages    []MCPPromptMessage `json:"messages"`
+}
+
+// MCPPromptMessage 提示消息
+type MCPPromptMessage struct {
+	Role    string         `json:"role"` // user, assistant
+	Content MCPTextContent `json:"content"`
+}
+
+// MCPTextContent 文本内容
+type MCPTextContent struct {
+	Type string `json:"type"` // text
+	Text string `json:"text"`
+}
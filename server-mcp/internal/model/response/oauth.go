@@ -0,0 +1,10 @@
+package response
+
+// OAuthProtectedResourceMetadata OAuth 受保护资源元数据（RFC 9728）
+type OAuthProtectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	ScopesSupported        []string `json:"scopes_supported,omitempty"`
+	ResourceName           string   `json:"resource_name,omitempty"`
+}
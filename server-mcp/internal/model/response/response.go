@@ -58,6 +58,11 @@ func NoAuth(message string, c *gin.Context) {
 	Result(http.StatusForbidden, ERROR, gin.H{"reload": true}, message, c)
 }
 
+// Unauthorized 401 未认证（调用方需同时设置 WWW-Authenticate 响应头）
+func Unauthorized(message string, c *gin.Context) {
+	Result(http.StatusUnauthorized, ERROR, nil, message, c)
+}
+
 func Forbidden(message string, c *gin.Context) {
 	c.JSON(http.StatusForbidden, Response{
 		Code: ERROR,
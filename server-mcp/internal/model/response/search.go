@@ -7,6 +7,18 @@ type SearchResult struct {
 	Page    int                `json:"page"`
 	Limit   int                `json:"limit"`
 	HasMore bool               `json:"hasMore"`
+
+	TokensUsed int `json:"tokensUsed"` // 本页结果的 token 总数
+
+	QueryExpansion []QueryExpansion `json:"queryExpansion,omitempty"` // 各 topic 的查询扩展情况（未启用查询扩展时为空）
+}
+
+// QueryExpansion 单个 topic 的 LLM 查询扩展情况
+type QueryExpansion struct {
+	Topic   string   `json:"topic"`
+	Applied bool     `json:"applied"`           // 是否应用了扩展（LLM 失败或超时时按原 topic 检索）
+	Queries []string `json:"queries,omitempty"` // 扩展出的子查询
+	HyDE    bool     `json:"hyde,omitempty"`    // 是否使用了假设答案段落（HyDE）做向量检索
 }
 
 // SearchResultItem 搜索结果项
@@ -14,14 +26,15 @@ type SearchResultItem struct {
 	ChunkID     uint    `json:"chunk_id"`
 	UploadID    uint    `json:"upload_id"`
 	LibraryID   uint    `json:"library_id"`
-	Version     string  `json:"version"`     // 文档版本
-	Mode        string  `json:"mode"`        // 类型：code 或 info
-	Title       string  `json:"title"`       // LLM 生成的标题（code mode）或 headers 层级（info mode）
-	Description string  `json:"description"` // LLM 生成的描述（code mode），info mode 为空
-	Source      string  `json:"source"`      // 文件来源路径
-	Language    string  `json:"language"`    // 代码语言（code mode），info mode 为空
-	Code        string  `json:"code"`        // 代码内容（code mode），info mode 为空
-	Content     string  `json:"content"`     // ChunkText 原文
-	Tokens      int     `json:"tokens"`      // token 数
-	Relevance   float64 `json:"relevance"`   // 最终相关性分数 0-1
+	Version     string  `json:"version"`             // 文档版本
+	Mode        string  `json:"mode"`                // 类型：code 或 info
+	Title       string  `json:"title"`               // LLM 生成的标题（code mode）或 headers 层级（info mode）
+	Description string  `json:"description"`         // LLM 生成的描述（code mode），info mode 为空
+	Source      string  `json:"source"`              // 文件来源路径
+	Language    string  `json:"language"`            // 代码语言（code mode），info mode 为空
+	Code        string  `json:"code"`                // 代码内容（code mode），info mode 为空
+	Content     string  `json:"content"`             // ChunkText 原文
+	Tokens      int     `json:"tokens"`              // token 数（截断时为截断后的 token 数）
+	Truncated   bool    `json:"truncated,omitempty"` // 是否因超出 token 预算被截断
+	Relevance   float64 `json:"relevance"`           // 最终相关性分数 0-1
 }
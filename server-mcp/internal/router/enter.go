@@ -11,6 +11,8 @@ type RouterGroup struct {
 	ApiKeyRouter
 	ActivityLogRouter
 	StatsRouter
+	PromptRouter
+	OAuthRouter
 }
 
 var RouterGroupApp = new(RouterGroup)
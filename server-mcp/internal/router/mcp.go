@@ -14,6 +14,8 @@ func (m *MCPRouter) InitMCPRouter(Router *gin.RouterGroup) {
 	{
 		//mcpRouter.GET("health", mcpApi.Health)
 		//mcpRouter.GET("tools", mcpApi.ListTools)
-		mcpRouter.POST("", mcpApi.HandleRequest)
+		mcpRouter.POST("", mcpApi.HandleRequest)         // JSON-RPC 请求（SSE 协议携带 ?sessionId=xxx）
+		mcpRouter.GET("", mcpApi.HandleSSE)              // 建立 SSE 连接 / 打开会话通知流（携带 Mcp-Session-Id）
+		mcpRouter.DELETE("", mcpApi.HandleDeleteSession) // 终止 Streamable HTTP 会话
 	}
 }
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"go-mcp-context/internal/api"
+	"go-mcp-context/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthRouter struct{}
+
+// InitOAuthRouter 初始化 OAuth 受保护资源元数据路由（无需认证，未启用 OAuth 时不注册）
+func (o *OAuthRouter) InitOAuthRouter(Router *gin.RouterGroup) {
+	oauthService := service.ServiceGroupApp.OAuthService
+	if !oauthService.Enabled() {
+		return
+	}
+	oauthApi := api.ApiGroupApp.OAuthApi
+	for _, path := range oauthService.MetadataPaths() {
+		Router.GET(path, oauthApi.ProtectedResourceMetadata)
+	}
+}
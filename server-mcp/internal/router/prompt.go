@@ -0,0 +1,27 @@
+package router
+
+import (
+	"go-mcp-context/internal/api"
+	"go-mcp-context/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromptRouter struct{}
+
+// InitPromptRouter 初始化 MCP 提示模板路由（需要 SSO JWT 认证）
+// 提示模板对所有 MCP 客户端生效，增删改需要管理员权限
+func (p *PromptRouter) InitPromptRouter(Router *gin.RouterGroup) {
+	promptRouter := Router.Group("prompts")
+	promptAdminRouter := Router.Group("prompts").Use(middleware.AdminAuth())
+	promptApi := api.ApiGroupApp.PromptApi
+	{
+		promptRouter.GET("", promptApi.List)   // 列表查询
+		promptRouter.GET(":id", promptApi.Get) // 详情查询
+	}
+	{
+		promptAdminRouter.POST("", promptApi.Create)      // 创建
+		promptAdminRouter.PUT(":id", promptApi.Update)    // 更新
+		promptAdminRouter.DELETE(":id", promptApi.Delete) // 删除
+	}
+}
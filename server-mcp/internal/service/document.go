@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"go-mcp-context/internal/event"
 	dbmodel "go-mcp-context/internal/model/database"
 	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/model/response"
@@ -352,6 +353,12 @@ func (s *DocumentService) Delete(id uint) error {
 		Where("upload_id = ? AND deleted_at IS NULL", id).
 		Updates(map[string]interface{}{"status": "deleted", "deleted_at": now})
 
+	// 通知文档变更
+	var doc dbmodel.DocumentUpload
+	if err := global.DB.Unscoped().Select("library_id", "version").First(&doc, id).Error; err == nil {
+		event.Publish(event.Event{Topic: event.TopicDocsChanged, LibraryID: doc.LibraryID, Version: doc.Version})
+	}
+
 	return nil
 }
 
@@ -8,6 +8,8 @@ type ServiceGroup struct {
 	ApiKeyService
 	ActivityLogService
 	StatsService
+	PromptService
+	OAuthService
 }
 
 var ServiceGroupApp = new(ServiceGroup)
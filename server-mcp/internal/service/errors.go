@@ -1,6 +1,10 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // 服务层通用错误
 var (
@@ -10,4 +14,39 @@ var (
 	ErrInvalidParams = errors.New("参数无效")
 	ErrUnauthorized  = errors.New("未授权")
 	ErrForbidden     = errors.New("禁止访问")
+
+	ErrVersionNotFound      = errors.New("版本不存在")
+	ErrEmbeddingUnavailable = errors.New("向量服务不可用")
+
+	ErrUnsupportedProtocolVersion = errors.New("不支持的协议版本")
+	ErrElicitationUnavailable     = errors.New("无法向客户端发起 elicitation")
 )
+
+// VersionNotFoundError 请求的库版本不存在，携带可用版本列表
+type VersionNotFoundError struct {
+	Version   string
+	Available []string
+}
+
+func (e *VersionNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s (available: %s)", ErrVersionNotFound, e.Version, strings.Join(e.Available, ", "))
+}
+
+// Unwrap 支持 errors.Is(err, ErrVersionNotFound)
+func (e *VersionNotFoundError) Unwrap() error {
+	return ErrVersionNotFound
+}
+
+// ToolParamsError 工具参数无效（以 JSON-RPC -32602 错误返回，而非 isError 结果）
+type ToolParamsError struct {
+	Message string
+}
+
+func (e *ToolParamsError) Error() string {
+	return e.Message
+}
+
+// Unwrap 支持 errors.Is(err, ErrInvalidParams)
+func (e *ToolParamsError) Unwrap() error {
+	return ErrInvalidParams
+}
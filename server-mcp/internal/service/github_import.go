@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"go-mcp-context/internal/event"
 	dbmodel "go-mcp-context/internal/model/database"
 	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/model/response"
@@ -272,6 +273,8 @@ func (s *GitHubImportService) ImportFromGitHub(ctx context.Context, libraryID ui
 		actLogger.Warning(actlog.EventGHImportComplete, fmt.Sprintf("导入完成: %s@%s (成功 %d, 失败 %d)", req.Repo, version, successCount, failCount))
 	}
 
+	event.Publish(event.Event{Topic: event.TopicDocsChanged, LibraryID: libraryID, Version: version})
+
 	sendProgress(progressChan, response.GitHubImportProgress{
 		Stage:   "completed",
 		Current: successCount + failCount,
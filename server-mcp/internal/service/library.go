@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"go-mcp-context/internal/event"
 	dbmodel "go-mcp-context/internal/model/database"
 	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/model/response"
@@ -113,6 +114,7 @@ func (s *LibraryService) Create(req *request.LibraryCreate) (*dbmodel.Library, e
 	// 异步生成向量
 	go s.generateLibraryEmbedding(library.ID, library.Name, library.Description)
 
+	event.Publish(event.Event{Topic: event.TopicLibrariesChanged, LibraryID: library.ID})
 	return library, nil
 }
 
@@ -141,8 +143,13 @@ func (s *LibraryService) generateLibraryEmbedding(libraryID uint, name, descript
 
 // GetByID 根据 ID 获取库
 func (s *LibraryService) GetByID(id uint) (*dbmodel.Library, error) {
+	return s.GetByIDWithContext(context.Background(), id)
+}
+
+// GetByIDWithContext 获取库详情（ctx 取消时中止查询）
+func (s *LibraryService) GetByIDWithContext(ctx context.Context, id uint) (*dbmodel.Library, error) {
 	var library dbmodel.Library
-	if err := global.DB.First(&library, id).Error; err != nil {
+	if err := global.DB.WithContext(ctx).First(&library, id).Error; err != nil {
 		return nil, err
 	}
 	return &library, nil
@@ -218,6 +225,7 @@ func (s *LibraryService) InitFromGitHub(ctx context.Context, githubURL string, c
 	// 异步生成向量
 	go s.generateLibraryEmbedding(library.ID, library.Name, library.Description)
 
+	event.Publish(event.Event{Topic: event.TopicLibrariesChanged, LibraryID: library.ID})
 	return &InitFromGitHubResult{
 		Library:       library,
 		DefaultBranch: repoInfo.DefaultBranch,
@@ -228,16 +236,29 @@ func (s *LibraryService) InitFromGitHub(ctx context.Context, githubURL string, c
 
 // Update 更新库（只允许修改 name 和 description）
 func (s *LibraryService) Update(id uint, req *request.LibraryUpdate) (*dbmodel.Library, error) {
+	if err := validateSearchWeights(req.SearchWeights); err != nil {
+		return nil, err
+	}
+
 	var library dbmodel.Library
 	if err := global.DB.First(&library, id).Error; err != nil {
 		return nil, err
 	}
 
-	// 只更新 name 和 description 字段，避免触碰 embedding 字段
-	if err := global.DB.Model(&library).Updates(map[string]interface{}{
+	// 只更新 name、description 和检索权重字段，避免触碰 embedding 字段
+	updates := map[string]interface{}{
 		"name":        req.Name,
 		"description": req.Description,
-	}).Error; err != nil {
+	}
+	// 检索权重：不传保持不变，传空对象恢复全局配置（新权重通过缓存 key 立即生效）
+	if req.SearchWeights != nil {
+		library.SearchWeights = req.SearchWeights
+		if req.SearchWeights.IsEmpty() {
+			library.SearchWeights = nil
+		}
+		updates["search_weights"] = library.SearchWeights
+	}
+	if err := global.DB.Model(&library).Updates(updates).Error; err != nil {
 		return nil, err
 	}
 
@@ -248,6 +269,7 @@ func (s *LibraryService) Update(id uint, req *request.LibraryUpdate) (*dbmodel.L
 	// 异步重新生成向量（因为 name 或 description 已更新）
 	go s.generateLibraryEmbedding(library.ID, library.Name, library.Description)
 
+	event.Publish(event.Event{Topic: event.TopicLibrariesChanged, LibraryID: library.ID})
 	return &library, nil
 }
 
@@ -274,6 +296,7 @@ func (s *LibraryService) Delete(id uint) error {
 		Where("library_id = ? AND deleted_at IS NULL", id).
 		Updates(map[string]interface{}{"status": "deleted", "deleted_at": now})
 
+	event.Publish(event.Event{Topic: event.TopicLibrariesChanged, LibraryID: id})
 	return nil
 }
 
@@ -339,6 +362,7 @@ func (s *LibraryService) GetLibraryInfo(id uint) (*response.LibraryInfo, error)
 		ChunkCount:     int(stats.ChunkCount),
 		TokenCount:     int(stats.TokenCount),
 		Status:         library.Status,
+		SearchWeights:  library.SearchWeights,
 		CreatedAt:      library.CreatedAt,
 		UpdatedAt:      library.UpdatedAt,
 	}, nil
@@ -640,6 +664,7 @@ func (s *LibraryService) DeleteVersion(libraryID uint, version string) error {
 		return err
 	}
 
+	event.Publish(event.Event{Topic: event.TopicDocsChanged, LibraryID: libraryID, Version: version})
 	return nil
 }
 
@@ -1013,6 +1038,7 @@ func (s *LibraryService) RefreshVersionWithCallback(libraryID uint, version stri
 	if err := searchService.InvalidateLibraryCache(libraryID, version); err != nil {
 		log.Printf("[RefreshVersion] WARNING: Failed to invalidate cache: %v", err)
 	}
+	event.Publish(event.Event{Topic: event.TopicDocsChanged, LibraryID: libraryID, Version: version})
 
 	// 记录完成日志
 	if successCount == total {
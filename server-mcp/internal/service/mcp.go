@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	dbmodel "go-mcp-context/internal/model/database"
@@ -13,8 +16,12 @@ import (
 
 	"github.com/agnivade/levenshtein"
 	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm/clause"
 )
 
+// MaxCrossLibraries 跨库检索最多涉及的库数（libraryIds 上限，未指定时取与 topic 最相关的库）
+const MaxCrossLibraries = 20
+
 type MCPService struct {
 	searchService *SearchService
 }
@@ -29,17 +36,38 @@ func NewMCPService() *MCPService {
 // SearchLibraries 搜索库（MCP 工具）
 // 策略：向量搜索优先，模糊匹配降级
 func (s *MCPService) SearchLibraries(req *request.MCPSearchLibraries) (*response.MCPSearchLibrariesResult, error) {
+	return s.SearchLibrariesWithContext(context.Background(), req)
+}
+
+// SearchLibrariesWithContext 搜索库（ctx 可携带 MCPLogger，向客户端推送检索过程日志）
+func (s *MCPService) SearchLibrariesWithContext(ctx context.Context, req *request.MCPSearchLibraries) (*response.MCPSearchLibrariesResult, error) {
 	var libraries []dbmodel.Library
-	ctx := context.Background()
 
 	// 1. 尝试向量搜索
 	vectorLibs, vectorErr := s.vectorSearchLibraries(ctx, req.LibraryName, 10)
 	if vectorErr == nil && len(vectorLibs) > 0 {
 		libraries = vectorLibs
+		mcpLog(ctx, "debug", "search-libraries", map[string]interface{}{
+			"strategy": "vector",
+			"matches":  len(vectorLibs),
+		})
 	} else {
+		if vectorErr != nil {
+			mcpLog(ctx, "warning", "search-libraries", map[string]interface{}{
+				"strategy": "fuzzy",
+				"reason":   "vector search failed, falling back to name matching",
+				"error":    vectorErr.Error(),
+			})
+		} else {
+			mcpLog(ctx, "info", "search-libraries", map[string]interface{}{
+				"strategy": "fuzzy",
+				"reason":   "no semantic match, falling back to name matching",
+			})
+		}
+
 		// 2. 向量搜索失败或无结果，降级到模糊匹配
 		// 前缀匹配
-		err := global.DB.Where("status = ? AND name ILIKE ?", "active", req.LibraryName+"%").
+		err := global.DB.WithContext(ctx).Where("status = ? AND name ILIKE ?", "active", req.LibraryName+"%").
 			Order("name ASC").
 			Limit(10).
 			Find(&libraries).Error
@@ -51,7 +79,7 @@ func (s *MCPService) SearchLibraries(req *request.MCPSearchLibraries) (*response
 		// 如果前缀匹配结果不足，尝试包含匹配
 		if len(libraries) < 5 {
 			var moreLibraries []dbmodel.Library
-			global.DB.Where("status = ? AND name ILIKE ? AND name NOT ILIKE ?",
+			global.DB.WithContext(ctx).Where("status = ? AND name ILIKE ? AND name NOT ILIKE ?",
 				"active", "%"+req.LibraryName+"%", req.LibraryName+"%").
 				Order("name ASC").
 				Limit(10 - len(libraries)).
@@ -68,7 +96,7 @@ func (s *MCPService) SearchLibraries(req *request.MCPSearchLibraries) (*response
 	for _, lib := range libraries {
 		// 统计文档片段数
 		var snippetCount int64
-		global.DB.Model(&dbmodel.DocumentChunk{}).
+		global.DB.WithContext(ctx).Model(&dbmodel.DocumentChunk{}).
 			Where("library_id = ? AND status = ?", lib.ID, "active").
 			Count(&snippetCount)
 
@@ -111,36 +139,70 @@ func (s *MCPService) SearchLibraries(req *request.MCPSearchLibraries) (*response
 // GetLibraryDocs 获取库文档（MCP 工具）
 // 支持两种模式：
 // 1. 指定 libraryID：在特定库中搜索
-// 2. 不指定 libraryID（为 0）：全局搜索所有库
+// 2. 不指定 libraryID（为 0）：跨库搜索 LibraryIDs 中的库（为空时与 topic 最相关的 MaxCrossLibraries 个库），结果按库分组
 func (s *MCPService) GetLibraryDocs(req *request.MCPGetLibraryDocs) (*response.MCPGetLibraryDocsResult, error) {
+	return s.GetLibraryDocsWithContext(context.Background(), req)
+}
+
+// GetLibraryDocsWithContext 获取库文档（ctx 可携带 MCPLogger，向客户端推送检索过程日志）
+func (s *MCPService) GetLibraryDocsWithContext(ctx context.Context, req *request.MCPGetLibraryDocs) (*response.MCPGetLibraryDocsResult, error) {
 	// 分页参数
 	page := req.Page
 	if page < 1 || page > 10 {
 		page = 1
 	}
-	limit := 10 // MCP 每页固定 10 条
+	limit := 10 // MCP 每页固定 10 条（指定 token 预算时按预算装填）
 
 	version := req.Version
 
+	// 检索范围内的库（用于标注结果所属库）
+	libraries := make(map[uint]*dbmodel.Library)
+
 	// 如果指定了 libraryID，验证库是否存在
 	var libraryID uint
+	var library *dbmodel.Library
+	var scopes []request.SearchScope
 	if req.LibraryID > 0 {
 		libraryService := &LibraryService{}
-		library, err := libraryService.GetByID(req.LibraryID)
+		var err error
+		library, err = libraryService.GetByIDWithContext(ctx, req.LibraryID)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			return nil, ErrNotFound
 		}
 		libraryID = library.ID
+		libraries[library.ID] = library
+
+		// 解析版本（默认版本、别名、语义化版本范围），不存在时返回可用版本供调用方修正
+		resolved, err := resolveLibraryVersion(library, version)
+		if err != nil {
+			return nil, err
+		}
+		version = resolved
+	} else {
+		var err error
+		scopes, err = s.crossLibraryScopes(ctx, req, libraries)
+		if err != nil {
+			return nil, err
+		}
+		if len(scopes) == 0 {
+			return &response.MCPGetLibraryDocsResult{Documents: []response.MCPDocumentChunk{}, Page: page}, nil
+		}
 	}
 
-	// 执行搜索（libraryID 为 0 时全局搜索）
-	searchResult, err := s.searchService.SearchDocuments(&request.Search{
-		LibraryID: libraryID, // 0 表示全局搜索
-		Query:     req.Topic,
-		Mode:      req.Mode,
-		Version:   version,
-		Page:      page,
-		Limit:     limit,
+	// 执行搜索（跨库检索时按 scopes 过滤）
+	searchResult, err := s.searchService.SearchDocumentsWithContext(ctx, &request.Search{
+		LibraryID:   libraryID,
+		Query:       req.Topic,
+		Mode:        req.Mode,
+		Version:     version,
+		Page:        page,
+		Limit:       limit,
+		Scopes:      scopes,
+		TokenBudget: req.Tokens,
+		Library:     library,
 	})
 	if err != nil {
 		return nil, err
@@ -149,6 +211,7 @@ func (s *MCPService) GetLibraryDocs(req *request.MCPGetLibraryDocs) (*response.M
 	documents := make([]response.MCPDocumentChunk, 0, len(searchResult.Results))
 	for _, r := range searchResult.Results {
 		doc := response.MCPDocumentChunk{
+			LibraryID:   r.LibraryID,
 			Title:       r.Title,
 			Description: r.Description, // code mode 有值，info mode 为空
 			Source:      r.Source,
@@ -157,8 +220,12 @@ func (s *MCPService) GetLibraryDocs(req *request.MCPGetLibraryDocs) (*response.M
 			Language:    r.Language, // code mode 有值，info mode 为空
 			Code:        r.Code,     // code mode 有值，info mode 为空
 			Tokens:      r.Tokens,
+			Truncated:   r.Truncated,
 			Relevance:   r.Relevance,
 		}
+		if lib, ok := libraries[r.LibraryID]; ok {
+			doc.Library = lib.Name
+		}
 		// info 模式才返回 content（chunk_text）
 		if r.Mode == "info" {
 			doc.Content = r.Content
@@ -166,17 +233,142 @@ func (s *MCPService) GetLibraryDocs(req *request.MCPGetLibraryDocs) (*response.M
 		documents = append(documents, doc)
 	}
 
-	// 统计 MCP 调用（如果有指定库）
+	// 跨库检索：按库分组（库按其最相关片段排序，组内保持相关性顺序）
+	if libraryID == 0 {
+		groupDocumentsByLibrary(documents)
+	}
+
+	// 统计 MCP 调用（按结果涉及的库）
+	for _, id := range documentLibraryIDs(documents, libraryID) {
+		stats.IncrementWithLibrary(id, dbmodel.MetricMCPGetLibraryDocs, 1)
+	}
+
+	result := &response.MCPGetLibraryDocsResult{
+		LibraryID:   libraryID,
+		Documents:   documents,
+		Page:        page,
+		HasMore:     searchResult.HasMore,
+		TokenBudget: req.Tokens,
+		TokensUsed:  searchResult.TokensUsed,
+
+		QueryExpansion: searchResult.QueryExpansion,
+	}
+	if libraryID > 0 {
+		result.Version = version
+		if req.Version != version {
+			result.RequestedVersion = req.Version
+		}
+	}
+	return result, nil
+}
+
+// crossLibraryScopes 跨库检索的库版本范围
+// 每个库使用其默认版本；指定了 version 且能在库中解析到对应版本时使用解析结果
+func (s *MCPService) crossLibraryScopes(ctx context.Context, req *request.MCPGetLibraryDocs, libraries map[uint]*dbmodel.Library) ([]request.SearchScope, error) {
+	if len(req.LibraryIDs) > MaxCrossLibraries {
+		return nil, fmt.Errorf("%w: libraryIds accepts at most %d libraries", ErrInvalidParams, MaxCrossLibraries)
+	}
+
+	var found []dbmodel.Library
+	if len(req.LibraryIDs) > 0 {
+		if err := global.DB.WithContext(ctx).Where("status = ? AND id IN ?", "active", req.LibraryIDs).
+			Order("id ASC").
+			Find(&found).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if found, err = s.topicLibraries(ctx, req.Topic, MaxCrossLibraries); err != nil {
+			return nil, err
+		}
+	}
+
+	// 指定的库必须都存在
+	if len(req.LibraryIDs) > 0 {
+		var missing []string
+		for _, id := range req.LibraryIDs {
+			if !slices.ContainsFunc(found, func(lib dbmodel.Library) bool { return lib.ID == id }) {
+				missing = append(missing, strconv.FormatUint(uint64(id), 10))
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%w: libraryIds %s", ErrNotFound, strings.Join(missing, ", "))
+		}
+	}
+
+	scopes := make([]request.SearchScope, 0, len(found))
+	for i := range found {
+		lib := &found[i]
+		version, err := resolveLibraryVersion(lib, req.Version)
+		if err != nil {
+			version, _ = resolveLibraryVersion(lib, "")
+		}
+		libraries[lib.ID] = lib
+		scopes = append(scopes, request.SearchScope{LibraryID: lib.ID, Version: version})
+	}
+	return scopes, nil
+}
+
+// topicLibraries 未指定库范围时参与跨库检索的库：与 topic 语义最相关的前 limit 个库
+// 检索范围随库总数增长会让 scope 过滤条件无限变长，因此只取最相关的部分；生成向量失败时取最近更新的库
+func (s *MCPService) topicLibraries(ctx context.Context, topic string, limit int) ([]dbmodel.Library, error) {
+	query := global.DB.WithContext(ctx).Where("status = ?", "active").Limit(limit)
+
+	queryVector, err := global.Embedding.EmbedWithContext(ctx, topic)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		global.Log.Warn(fmt.Sprintf("跨库检索生成 topic 向量失败，使用最近更新的库: %v", err))
+		query = query.Order("updated_at DESC")
+	} else {
+		// 没有向量的库排在最后
+		query = query.Order(clause.OrderBy{
+			Expression: clause.Expr{SQL: "embedding <=> ? ASC NULLS LAST", Vars: []interface{}{pgvector.NewVector(queryVector)}},
+		})
+	}
+
+	var libraries []dbmodel.Library
+	if err := query.Find(&libraries).Error; err != nil {
+		return nil, err
+	}
+	return libraries, nil
+}
+
+// groupDocumentsByLibrary 将文档按库分组（稳定排序，组顺序为库首次出现的顺序）
+func groupDocumentsByLibrary(documents []response.MCPDocumentChunk) {
+	order := make(map[uint]int)
+	for _, doc := range documents {
+		if _, ok := order[doc.LibraryID]; !ok {
+			order[doc.LibraryID] = len(order)
+		}
+	}
+	sort.SliceStable(documents, func(i, j int) bool {
+		return order[documents[i].LibraryID] < order[documents[j].LibraryID]
+	})
+}
+
+// documentLibraryIDs 结果涉及的库ID（单库检索时为该库）
+func documentLibraryIDs(documents []response.MCPDocumentChunk, libraryID uint) []uint {
 	if libraryID > 0 {
-		stats.IncrementWithLibrary(libraryID, dbmodel.MetricMCPGetLibraryDocs, 1)
+		return []uint{libraryID}
 	}
+	var ids []uint
+	for _, doc := range documents {
+		if !slices.Contains(ids, doc.LibraryID) {
+			ids = append(ids, doc.LibraryID)
+		}
+	}
+	return ids
+}
 
-	return &response.MCPGetLibraryDocsResult{
-		LibraryID: libraryID,
-		Documents: documents,
-		Page:      page,
-		HasMore:   searchResult.HasMore,
-	}, nil
+// libraryVersions 库的可用版本（含默认版本）
+func libraryVersions(library *dbmodel.Library) []string {
+	versions := append([]string{}, library.Versions...)
+	if library.DefaultVersion != "" && !slices.Contains(versions, library.DefaultVersion) {
+		versions = append(versions, library.DefaultVersion)
+	}
+	return versions
 }
 
 // calculateMatchScore 计算名称匹配分数
@@ -253,7 +445,7 @@ func (s *MCPService) GetLibraryByID(id uint) (*dbmodel.Library, error) {
 // 返回：库列表、错误
 func (s *MCPService) vectorSearchLibraries(ctx context.Context, queryText string, limit int) ([]dbmodel.Library, error) {
 	// 1. 生成查询向量（使用 CachedEmbeddingService，自动缓存）
-	queryVector, err := global.Embedding.Embed(queryText)
+	queryVector, err := global.Embedding.EmbedWithContext(ctx, queryText)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
@@ -263,7 +455,7 @@ func (s *MCPService) vectorSearchLibraries(ctx context.Context, queryText string
 	// cosine distance: 0=完全相同, 1=正交(无关), 2=完全相反
 	// 根据实际测试，相关库的距离通常 < 0.7，不相关的 > 0.7
 	var libraries []dbmodel.Library
-	query := global.DB.Model(&dbmodel.Library{}).
+	query := global.DB.WithContext(ctx).Model(&dbmodel.Library{}).
 		Select("*, embedding <=> ? as distance", pgvector.NewVector(queryVector)).
 		Where("status = ? AND embedding IS NOT NULL AND (embedding <=> ?) < 0.7", "active", pgvector.NewVector(queryVector)). // 只返回相关的库
 		Order("distance ASC").
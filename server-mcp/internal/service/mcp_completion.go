@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	dbmodel "go-mcp-context/internal/model/database"
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/pkg/global"
+)
+
+// maxCompletionValues 单次补全返回的最大候选数（MCP 规范上限）
+const maxCompletionValues = 100
+
+// completionResourceTemplates 支持补全的资源模板
+var completionResourceTemplates = map[string]bool{
+	"go-mcp-context:///library/{libraryId}":                      true,
+	"go-mcp-context:///docs/chunk/{libraryId}/{version}/{topic}": true,
+}
+
+// Complete 处理 completion/complete 请求
+// 按参数名补全：libraryId/library 来自库列表，version/from/to 来自库的版本，topic 来自文档标题与历史调用
+func (s *MCPService) Complete(ctx context.Context, req *request.MCPComplete) (*response.MCPCompleteResult, error) {
+	switch req.Ref.Type {
+	case "ref/resource":
+		if !completionResourceTemplates[req.Ref.URI] {
+			return nil, fmt.Errorf("%w: unknown resource template %s", ErrInvalidParams, req.Ref.URI)
+		}
+	case "ref/prompt":
+		if req.Ref.Name == "" {
+			return nil, fmt.Errorf("%w: missing prompt name", ErrInvalidParams)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported ref type %s", ErrInvalidParams, req.Ref.Type)
+	}
+
+	var (
+		values []string
+		err    error
+	)
+	switch req.ArgumentName {
+	case "libraryId":
+		values, err = s.completeLibraryIDs(ctx, req.ArgumentValue)
+	case "library":
+		values, err = s.completeLibraryNames(ctx, req.ArgumentValue)
+	case "version", "from", "to":
+		values, err = s.completeVersions(req.Arguments, req.ArgumentValue)
+	case "topic":
+		values, err = s.completeTopics(req.Arguments, req.ArgumentValue)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	completion := response.MCPCompletion{
+		Values: make([]string, 0, len(values)),
+		Total:  len(values),
+	}
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	completion.Values = append(completion.Values, values...)
+
+	return &response.MCPCompleteResult{Completion: completion}, nil
+}
+
+// completeLibraryNames 补全库名
+// 前缀匹配优先，其次包含匹配，最后追加向量搜索的语义匹配
+func (s *MCPService) completeLibraryNames(ctx context.Context, value string) ([]string, error) {
+	libraries, err := s.matchLibraries(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(libraries))
+	for _, lib := range libraries {
+		names = append(names, lib.Name)
+	}
+	return names, nil
+}
+
+// completeLibraryIDs 补全库ID
+// 输入为数字时按ID前缀匹配，否则按库名检索后返回对应ID
+func (s *MCPService) completeLibraryIDs(ctx context.Context, value string) ([]string, error) {
+	if _, err := strconv.ParseUint(value, 10, 64); err == nil {
+		var ids []uint
+		if err := global.DB.WithContext(ctx).Model(&dbmodel.Library{}).
+			Where("status = ? AND CAST(id AS TEXT) LIKE ?", "active", value+"%").
+			Order("id ASC").
+			Limit(maxCompletionValues+1).
+			Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, strconv.FormatUint(uint64(id), 10))
+		}
+		return values, nil
+	}
+
+	libraries, err := s.matchLibraries(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(libraries))
+	for _, lib := range libraries {
+		values = append(values, strconv.FormatUint(uint64(lib.ID), 10))
+	}
+	return values, nil
+}
+
+// matchLibraries 按名称匹配库（去重，保持匹配优先级顺序）
+func (s *MCPService) matchLibraries(ctx context.Context, value string) ([]dbmodel.Library, error) {
+	limit := maxCompletionValues + 1
+
+	// 未输入时列出全部库
+	if value == "" {
+		var libraries []dbmodel.Library
+		err := global.DB.WithContext(ctx).Where("status = ?", "active").
+			Order("name ASC").
+			Limit(limit).
+			Find(&libraries).Error
+		return libraries, err
+	}
+
+	var libraries []dbmodel.Library
+	escaped := escapeLikePattern(value)
+	if err := global.DB.WithContext(ctx).Where(`status = ? AND name ILIKE ? ESCAPE '\'`, "active", escaped+"%").
+		Order("name ASC").
+		Limit(limit).
+		Find(&libraries).Error; err != nil {
+		return nil, err
+	}
+
+	var contains []dbmodel.Library
+	if err := global.DB.WithContext(ctx).Where(`status = ? AND name ILIKE ? ESCAPE '\' AND name NOT ILIKE ? ESCAPE '\'`,
+		"active", "%"+escaped+"%", escaped+"%").
+		Order("name ASC").
+		Limit(limit).
+		Find(&contains).Error; err != nil {
+		return nil, err
+	}
+	libraries = append(libraries, contains...)
+
+	// 语义匹配（如 "web framework" -> gin），失败时仅使用名称匹配
+	vectorLibs, err := s.vectorSearchLibraries(ctx, value, 10)
+	if err != nil {
+		mcpLog(ctx, "debug", "completion", map[string]interface{}{
+			"reason": "vector search failed, using name matching only",
+			"error":  err.Error(),
+		})
+	}
+	libraries = append(libraries, vectorLibs...)
+
+	seen := make(map[uint]bool, len(libraries))
+	unique := make([]dbmodel.Library, 0, len(libraries))
+	for _, lib := range libraries {
+		if seen[lib.ID] {
+			continue
+		}
+		seen[lib.ID] = true
+		unique = append(unique, lib)
+	}
+	return unique, nil
+}
+
+// completeVersions 补全版本号（需要 context 中已填写库）
+func (s *MCPService) completeVersions(args map[string]string, value string) ([]string, error) {
+	lib, err := s.resolveCompletionLibrary(args)
+	if err != nil || lib == nil {
+		return nil, err
+	}
+
+	versions := []string(lib.Versions)
+	if len(versions) == 0 && lib.DefaultVersion != "" {
+		versions = []string{lib.DefaultVersion}
+	}
+
+	values := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if strings.HasPrefix(strings.ToLower(v), strings.ToLower(value)) {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// completeTopics 补全主题（需要 context 中已填写库）
+// 历史调用中最常用的 topic 优先，其次是文档块标题
+func (s *MCPService) completeTopics(args map[string]string, value string) ([]string, error) {
+	lib, err := s.resolveCompletionLibrary(args)
+	if err != nil || lib == nil {
+		return nil, err
+	}
+
+	limit := maxCompletionValues + 1
+	pattern := "%" + escapeLikePattern(value) + "%"
+
+	// 1. 历史 get-library-docs 调用的 topic（按调用次数排序）
+	var pastTopics []string
+	if err := global.DB.Model(&dbmodel.MCPCallLog{}).
+		Select("params->'params'->'arguments'->>'topic' AS topic").
+		Where("func_name = ? AND tool_name = ? AND library_id = ? AND status = ?",
+			dbmodel.MCPFuncToolsCall, "get-library-docs", lib.ID, "success").
+		Where(`params->'params'->'arguments'->>'topic' <> '' AND params->'params'->'arguments'->>'topic' ILIKE ? ESCAPE '\'`, pattern).
+		Group("topic").
+		Order("COUNT(*) DESC").
+		Limit(limit).
+		Pluck("topic", &pastTopics).Error; err != nil {
+		return nil, err
+	}
+
+	// 2. 文档块标题（按访问热度排序）
+	query := global.DB.Model(&dbmodel.DocumentChunk{}).
+		Where(`library_id = ? AND status = ? AND title <> '' AND title ILIKE ? ESCAPE '\'`, lib.ID, "active", pattern)
+	if version := args["version"]; version != "" && version != "latest" {
+		query = query.Where("version = ?", version)
+	}
+
+	var titles []string
+	if err := query.Group("title").
+		Order("MAX(access_count) DESC, title ASC").
+		Limit(limit).
+		Pluck("title", &titles).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(pastTopics)+len(titles))
+	values := make([]string, 0, len(pastTopics)+len(titles))
+	for _, topic := range append(pastTopics, titles...) {
+		if seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		values = append(values, topic)
+	}
+	return values, nil
+}
+
+// resolveCompletionLibrary 从 context.arguments 中解析已填写的库（libraryId 或 library）
+// 未填写或找不到时返回 nil
+func (s *MCPService) resolveCompletionLibrary(args map[string]string) (*dbmodel.Library, error) {
+	ref := args["libraryId"]
+	if ref == "" {
+		ref = args["library"]
+	}
+	if ref == "" {
+		return nil, nil
+	}
+
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		return s.GetLibraryByID(uint(id))
+	}
+
+	var libraries []dbmodel.Library
+	if err := global.DB.Where(`status = ? AND name ILIKE ? ESCAPE '\'`, "active", escapeLikePattern(ref)).
+		Limit(1).
+		Find(&libraries).Error; err != nil {
+		return nil, err
+	}
+	if len(libraries) == 0 {
+		return nil, nil
+	}
+	return &libraries[0], nil
+}
+
+// likeEscaper 转义 LIKE 模式中的通配符与转义符，配合 ESCAPE '\' 使用
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLikePattern 转义用户输入，使其在 LIKE/ILIKE 中按字面匹配
+func escapeLikePattern(value string) string {
+	return likeEscaper.Replace(value)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	dbmodel "go-mcp-context/internal/model/database"
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// MCP 列表分页
+//
+// resources/list 与 resources/templates/list 使用不透明游标（cursor/nextCursor）分页。
+// 游标为 base64(JSON) + "." + HMAC 签名，携带分页位置、访问过滤与调用者，
+// 客户端无法伪造或修改，也不能把别人的游标用于 owned/accessible 过滤。
+
+const (
+	// defaultMCPPageSize 默认每页数量
+	defaultMCPPageSize = 100
+
+	cursorKindResources = "resources"
+	cursorKindTemplates = "templates"
+
+	// 访问过滤扩展（params.filter.access）
+	AccessFilterAccessible = "accessible" // 公共库与自己创建的库
+	AccessFilterOwned      = "owned"      // 仅自己创建的库
+)
+
+// mcpCursor 游标内容
+type mcpCursor struct {
+	Kind   string `json:"k"`           // 列表类型
+	After  uint   `json:"a,omitempty"` // resources: 上一页最后一个库ID
+	Offset int    `json:"o,omitempty"` // templates: 下一页起始位置
+	Access string `json:"f,omitempty"` // 访问过滤
+	UserID string `json:"u,omitempty"` // 调用者（有访问过滤时绑定）
+}
+
+// IsValidAccessFilter 判断访问过滤值是否合法（空表示不过滤）
+func IsValidAccessFilter(access string) bool {
+	return access == "" || access == AccessFilterAccessible || access == AccessFilterOwned
+}
+
+// mcpPageSize 每页数量（配置为空时使用默认值）
+func mcpPageSize() int {
+	if global.Config != nil && global.Config.MCP.PageSize > 0 {
+		return global.Config.MCP.PageSize
+	}
+	return defaultMCPPageSize
+}
+
+var (
+	// fallbackCursorSecret 未配置密钥时使用的进程内随机密钥
+	fallbackCursorSecret     []byte
+	fallbackCursorSecretOnce sync.Once
+)
+
+// cursorSecret 游标签名密钥
+// 依次使用 mcp.cursor_secret、jwt.access_token_secret；都未配置时使用进程内随机密钥，
+// 此时游标在重启后或跨实例失效，但不会以空密钥签名（空密钥签名可被任意伪造）
+func cursorSecret() []byte {
+	if global.Config != nil {
+		if global.Config.MCP.CursorSecret != "" {
+			return []byte(global.Config.MCP.CursorSecret)
+		}
+		if global.Config.JWT.AccessTokenSecret != "" {
+			return []byte(global.Config.JWT.AccessTokenSecret)
+		}
+	}
+
+	fallbackCursorSecretOnce.Do(func() {
+		fallbackCursorSecret = make([]byte, 32)
+		if _, err := rand.Read(fallbackCursorSecret); err != nil {
+			panic(fmt.Sprintf("生成游标签名密钥失败: %v", err))
+		}
+		if global.Log != nil {
+			global.Log.Warn("未配置 mcp.cursor_secret 与 jwt.access_token_secret，分页游标使用进程内随机密钥签名",
+				zap.String("hint", "多实例部署时请配置 mcp.cursor_secret"))
+		}
+	})
+	return fallbackCursorSecret
+}
+
+// signCursor 计算游标签名
+func signCursor(payload string) string {
+	mac := hmac.New(sha256.New, cursorSecret())
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// encodeCursor 编码并签名游标
+func encodeCursor(cursor *mcpCursor) string {
+	data, _ := json.Marshal(cursor)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signCursor(payload)
+}
+
+// decodeCursor 校验签名并解码游标，类型或调用者不匹配时返回 ErrInvalidParams
+func decodeCursor(raw, kind, userID string) (*mcpCursor, error) {
+	payload, signature, ok := strings.Cut(raw, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signCursor(payload))) {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidParams)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidParams)
+	}
+
+	var cursor mcpCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Kind != kind {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidParams)
+	}
+	if cursor.Access != "" && cursor.UserID != userID {
+		return nil, fmt.Errorf("%w: cursor belongs to another caller", ErrInvalidParams)
+	}
+	return &cursor, nil
+}
+
+// ListLibrariesPage 分页列出库（resources/list）
+// 按ID升序的 keyset 分页，返回当前页与下一页游标（没有更多时为空）
+func (s *MCPService) ListLibrariesPage(ctx context.Context, req *request.MCPListResources) ([]dbmodel.Library, string, error) {
+	cursor := &mcpCursor{Kind: cursorKindResources, Access: req.Access}
+	if req.Cursor != "" {
+		decoded, err := decodeCursor(req.Cursor, cursorKindResources, req.UserID)
+		if err != nil {
+			return nil, "", err
+		}
+		// 后续页沿用第一页的访问过滤
+		cursor = decoded
+	}
+	if !IsValidAccessFilter(cursor.Access) {
+		return nil, "", fmt.Errorf("%w: unknown access filter %s", ErrInvalidParams, cursor.Access)
+	}
+
+	pageSize := mcpPageSize()
+	query := global.DB.WithContext(ctx).Where("status = ? AND id > ?", "active", cursor.After)
+	switch cursor.Access {
+	case AccessFilterAccessible:
+		query = query.Where("created_by = '' OR created_by IS NULL OR created_by = ?", req.UserID)
+	case AccessFilterOwned:
+		query = query.Where("created_by = ?", req.UserID)
+	}
+
+	var libraries []dbmodel.Library
+	if err := query.Order("id ASC").Limit(pageSize + 1).Find(&libraries).Error; err != nil {
+		return nil, "", err
+	}
+
+	if len(libraries) <= pageSize {
+		return libraries, "", nil
+	}
+
+	libraries = libraries[:pageSize]
+	next := &mcpCursor{
+		Kind:   cursorKindResources,
+		After:  libraries[len(libraries)-1].ID,
+		Access: cursor.Access,
+	}
+	if next.Access != "" {
+		next.UserID = req.UserID
+	}
+	return libraries, encodeCursor(next), nil
+}
+
+// pageResourceTemplates 资源模板分页，返回当前页范围与下一页游标
+func pageResourceTemplates(rawCursor string, total int) (start, end int, nextCursor string, err error) {
+	if rawCursor != "" {
+		cursor, err := decodeCursor(rawCursor, cursorKindTemplates, "")
+		if err != nil {
+			return 0, 0, "", err
+		}
+		start = min(cursor.Offset, total)
+	}
+
+	end = min(start+mcpPageSize(), total)
+	if end < total {
+		nextCursor = encodeCursor(&mcpCursor{Kind: cursorKindTemplates, Offset: end})
+	}
+	return start, end, nextCursor, nil
+}
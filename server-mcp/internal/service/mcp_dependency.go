@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	dbmodel "go-mcp-context/internal/model/database"
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/pkg/bufferedwriter/stats"
+	"go-mcp-context/pkg/global"
+	"go-mcp-context/pkg/manifest"
+	"go-mcp-context/pkg/utils"
+)
+
+// 项目依赖解析（resolve-dependencies）
+//
+// 依赖按以下顺序匹配库，取分数最高的库：
+//  1. 来源地址：依赖对应的仓库（Go 模块路径 github.com/gin-gonic/gin -> gin-gonic/gin）与 Library.SourceURL 一致
+//  2. 别名：依赖的名称别名（包名、仓库名、npm scope 展开等）与库名或 SourceURL 仓库名一致
+//  3. 名称：calculateMatchScore 前缀/包含匹配（分数不低于 minDependencyMatchScore）
+//
+// 版本先按 resolveLibraryVersion 解析清单中的版本约束，解析不到时取语义化版本最接近的版本，都没有时使用默认版本
+
+// 依赖匹配
+const (
+	dependencyMatchSourceURL = "source_url"
+	dependencyMatchAlias     = "alias"
+	dependencyMatchName      = "name"
+
+	// minDependencyMatchScore 名称匹配的最低分数（低于该分数视为未匹配）
+	minDependencyMatchScore = 0.8
+	// maxResolvedDependencies 单次解析的依赖数上限
+	maxResolvedDependencies = 500
+	// maxManifestLength 单个清单文件的最大长度（字符）
+	maxManifestLength = 512 * 1024
+)
+
+// 版本匹配方式
+const (
+	versionMatchExact   = "exact"
+	versionMatchRange   = "range"
+	versionMatchClosest = "closest"
+	versionMatchDefault = "default"
+)
+
+// goMajorSuffixPattern Go 模块路径的主版本后缀（/v2）
+var goMajorSuffixPattern = regexp.MustCompile(`/v[0-9]+$`)
+
+// ResolveDependencies 将项目依赖清单解析为库与版本（MCP 工具）
+func (s *MCPService) ResolveDependencies(req *request.MCPResolveDependencies) (*response.MCPResolveDependenciesResult, error) {
+	return s.ResolveDependenciesWithContext(context.Background(), req)
+}
+
+// ResolveDependenciesWithContext 将项目依赖清单解析为库与版本
+// 清单格式不支持或内容无法解析时返回 *ToolParamsError
+func (s *MCPService) ResolveDependenciesWithContext(ctx context.Context, req *request.MCPResolveDependencies) (*response.MCPResolveDependenciesResult, error) {
+	type manifestDependency struct {
+		manifest.Dependency
+		filename string
+	}
+	var deps []manifestDependency
+	for _, m := range req.Manifests {
+		parsed, err := manifest.Parse(m.Filename, m.Content)
+		if err != nil {
+			return nil, &ToolParamsError{Message: fmt.Sprintf("%s: %v", m.Filename, err)}
+		}
+		for _, dep := range parsed {
+			deps = append(deps, manifestDependency{Dependency: dep, filename: m.Filename})
+		}
+	}
+	if len(deps) > maxResolvedDependencies {
+		return nil, &ToolParamsError{Message: fmt.Sprintf("too many dependencies: %d (max %d)", len(deps), maxResolvedDependencies)}
+	}
+
+	var libraries []dbmodel.Library
+	if len(deps) > 0 {
+		if err := global.DB.WithContext(ctx).Where("status = ?", "active").Order("id ASC").Find(&libraries).Error; err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]libraryMatchKeys, len(libraries))
+	for i := range libraries {
+		keys[i] = newLibraryMatchKeys(&libraries[i])
+	}
+
+	result := &response.MCPResolveDependenciesResult{
+		Dependencies: make([]response.MCPResolvedDependency, 0, len(deps)),
+	}
+	for _, dep := range deps {
+		resolved := response.MCPResolvedDependency{
+			Name:      dep.Name,
+			Ecosystem: dep.Ecosystem,
+			Manifest:  dep.filename,
+			Requested: dep.Version,
+			Dev:       dep.Dev,
+		}
+		if i, matchedBy, score := matchDependencyLibrary(dep.Dependency, keys); i >= 0 {
+			lib := &libraries[i]
+			resolved.LibraryID = lib.ID
+			resolved.Library = lib.Name
+			resolved.MatchedBy = matchedBy
+			resolved.Score = score
+			resolved.Version, resolved.VersionMatch = closestLibraryVersion(lib, dep.Dependency)
+			result.Matched++
+		}
+		result.Dependencies = append(result.Dependencies, resolved)
+	}
+
+	// 统计 MCP 调用（全局统计，不关联具体库）
+	stats.Increment(dbmodel.MetricMCPResolveDependencies, 1)
+
+	mcpLog(ctx, "debug", "resolve-dependencies", map[string]interface{}{
+		"dependencies": len(result.Dependencies),
+		"matched":      result.Matched,
+	})
+	return result, nil
+}
+
+// libraryMatchKeys 库的匹配键
+type libraryMatchKeys struct {
+	name    string   // 库名
+	source  string   // 规范化的 SourceURL（github 等托管平台为 owner/repo）
+	aliases []string // 规范化的库名与 GitHub 仓库名
+}
+
+func newLibraryMatchKeys(lib *dbmodel.Library) libraryMatchKeys {
+	keys := libraryMatchKeys{
+		name:    lib.Name,
+		source:  normalizeSourceURL(lib.SourceURL),
+		aliases: []string{normalizeDependencyName(lib.Name)},
+	}
+	if lib.SourceType == "github" && keys.source != "" {
+		repo := keys.source[strings.LastIndexByte(keys.source, '/')+1:]
+		keys.aliases = append(keys.aliases, normalizeDependencyName(repo))
+	}
+	return keys
+}
+
+// matchDependencyLibrary 为依赖选择分数最高的库，未匹配时返回 -1
+func matchDependencyLibrary(dep manifest.Dependency, libraries []libraryMatchKeys) (int, string, float64) {
+	source := dependencySource(dep)
+	aliases := dependencyAliases(dep)
+
+	best, bestBy, bestScore := -1, "", 0.0
+	for i, lib := range libraries {
+		by, score := "", 0.0
+		switch {
+		case source != "" && lib.source != "" && (source == lib.source || strings.HasPrefix(source, lib.source+"/")):
+			by, score = dependencyMatchSourceURL, 1.0
+		case slices.ContainsFunc(lib.aliases, func(alias string) bool { return slices.Contains(aliases, alias) }):
+			by, score = dependencyMatchAlias, 0.95
+		default:
+			for _, alias := range aliases {
+				if len(alias) < 3 {
+					continue // 过短的别名包含匹配误报太多
+				}
+				if s := calculateMatchScore(alias, lib.name); s >= minDependencyMatchScore && s > score {
+					by, score = dependencyMatchName, s
+				}
+			}
+		}
+		if score > bestScore {
+			best, bestBy, bestScore = i, by, score
+		}
+	}
+	return best, bestBy, bestScore
+}
+
+// dependencySource 依赖对应的源码地址（仅 Go 模块路径可推出，github 等托管平台为 owner/repo）
+func dependencySource(dep manifest.Dependency) string {
+	if dep.Ecosystem != manifest.EcosystemGo {
+		return ""
+	}
+	return normalizeSourceURL(goMajorSuffixPattern.ReplaceAllString(dep.Name, ""))
+}
+
+// dependencyAliases 依赖的名称别名（已规范化）
+func dependencyAliases(dep manifest.Dependency) []string {
+	var aliases []string
+	add := func(name string) {
+		if name = normalizeDependencyName(name); name != "" && !slices.Contains(aliases, name) {
+			aliases = append(aliases, name)
+		}
+	}
+
+	switch dep.Ecosystem {
+	case manifest.EcosystemGo:
+		// 模块路径最后一段（去掉主版本后缀），github.com/go-redis/redis/v9 -> redis
+		path := goMajorSuffixPattern.ReplaceAllString(dep.Name, "")
+		last := path[strings.LastIndexByte(path, '/')+1:]
+		add(last)
+		add(strings.TrimPrefix(last, "go-"))
+	case manifest.EcosystemNPM:
+		// @scope/name -> name、scope-name（@vue/router -> vue-router）
+		add(dep.Name)
+		if scope, name, ok := strings.Cut(strings.TrimPrefix(dep.Name, "@"), "/"); ok && strings.HasPrefix(dep.Name, "@") {
+			add(name)
+			add(scope + "-" + name)
+		}
+	case manifest.EcosystemPyPI:
+		add(dep.Name)
+		name := normalizeDependencyName(dep.Name)
+		add(strings.TrimPrefix(name, "python-"))
+		add(strings.TrimSuffix(name, "-python"))
+	case manifest.EcosystemMaven:
+		// groupId:artifactId -> artifactId、starter 前的部分（spring-boot-starter-web -> spring-boot）
+		_, artifact, _ := strings.Cut(dep.Name, ":")
+		add(artifact)
+		if i := strings.Index(artifact, "-starter"); i > 0 {
+			add(artifact[:i])
+		}
+		add(strings.TrimSuffix(artifact, "-core"))
+	default:
+		add(dep.Name)
+	}
+	return aliases
+}
+
+// normalizeDependencyName 规范化名称（小写，_ . 空格统一为 -）
+func normalizeDependencyName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "-", ".", "-", " ", "-").Replace(name)
+}
+
+// normalizeSourceURL 规范化源码地址：去掉协议、www、.git 与末尾斜杠，github.com 等托管平台取 owner/repo
+func normalizeSourceURL(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	source = strings.TrimPrefix(strings.TrimPrefix(source, "https://"), "http://")
+	source = strings.TrimPrefix(source, "www.")
+	source = strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+
+	for _, host := range []string{"github.com/", "gitlab.com/", "bitbucket.org/"} {
+		if rest, ok := strings.CutPrefix(source, host); ok {
+			parts := strings.SplitN(rest, "/", 3)
+			if len(parts) >= 2 {
+				return parts[0] + "/" + parts[1]
+			}
+			return rest
+		}
+	}
+	return source
+}
+
+// closestLibraryVersion 依赖版本约束对应的最接近的已索引版本及匹配方式
+func closestLibraryVersion(library *dbmodel.Library, dep manifest.Dependency) (string, string) {
+	constraint := dependencyVersionRange(dep)
+	if constraint == "" {
+		version, _ := resolveLibraryVersion(library, "")
+		return version, versionMatchDefault
+	}
+
+	if version, err := resolveLibraryVersion(library, constraint); err == nil {
+		if strings.EqualFold(trimVersionPrefix(version), trimVersionPrefix(constraint)) {
+			return version, versionMatchExact
+		}
+		return version, versionMatchRange
+	}
+
+	// 约束内没有已索引版本：取与约束中基准版本最接近的版本
+	if target, ok := baseConstraintVersion(constraint); ok {
+		if version, ok := nearestSemverVersion(libraryVersions(library), target); ok {
+			return version, versionMatchClosest
+		}
+	}
+
+	version, _ := resolveLibraryVersion(library, "")
+	return version, versionMatchDefault
+}
+
+// dependencyVersionRange 将清单中的版本约束转换为 resolveLibraryVersion 可识别的写法
+func dependencyVersionRange(dep manifest.Dependency) string {
+	version := strings.TrimSpace(dep.Version)
+	switch dep.Ecosystem {
+	case manifest.EcosystemGo:
+		return strings.TrimSuffix(version, "+incompatible")
+
+	case manifest.EcosystemPyPI:
+		// ==1.2 -> 1.2、~=1.2 -> ^1.2、~=1.2.3 -> ~1.2.3、逗号 -> 且；排除条件（!=）忽略
+		var terms []string
+		for _, term := range strings.Split(version, ",") {
+			term = strings.TrimSpace(term)
+			switch {
+			case term == "" || strings.HasPrefix(term, "!="):
+				continue
+			case strings.HasPrefix(term, "==="), strings.HasPrefix(term, "=="):
+				term = strings.TrimLeft(term, "=")
+			case strings.HasPrefix(term, "~="):
+				term = strings.TrimPrefix(term, "~=")
+				if strings.Count(term, ".") >= 2 {
+					term = "~" + term
+				} else {
+					term = "^" + term
+				}
+			}
+			terms = append(terms, strings.TrimSuffix(term, ".*"))
+		}
+		return strings.Join(terms, " ")
+
+	case manifest.EcosystemMaven:
+		// 区间写法 [1.0,2.0) 取下界
+		if strings.HasPrefix(version, "[") || strings.HasPrefix(version, "(") {
+			lower, _, _ := strings.Cut(strings.Trim(version, "[]()"), ",")
+			return strings.TrimSpace(lower)
+		}
+		if strings.Contains(version, "${") {
+			return "" // 未展开的属性
+		}
+		return version
+
+	default:
+		if version == "*" || strings.EqualFold(version, VersionAliasLatest) {
+			return ""
+		}
+		return version
+	}
+}
+
+// baseConstraintVersion 版本约束中的第一个版本号（^1.9.2 -> 1.9.2）
+func baseConstraintVersion(constraint string) (utils.Semver, bool) {
+	for _, field := range strings.Fields(constraint) {
+		if v, ok := utils.ParseSemver(strings.TrimLeft(field, "^~=<>!")); ok {
+			return v, true
+		}
+	}
+	return utils.Semver{}, false
+}
+
+// nearestSemverVersion 与目标版本最接近的版本（依次比较 major、minor、patch 差距，差距相同时取较高版本）
+func nearestSemverVersion(versions []string, target utils.Semver) (string, bool) {
+	best := ""
+	var bestV utils.Semver
+	var bestDist []int
+	for _, version := range versions {
+		v, ok := utils.ParseSemver(version)
+		if !ok {
+			continue
+		}
+		dist := []int{abs(v.Major - target.Major), abs(v.Minor - target.Minor), abs(v.Patch - target.Patch)}
+		c := slices.Compare(dist, bestDist)
+		if best == "" || c < 0 || (c == 0 && utils.CompareSemver(v, bestV) > 0) {
+			best, bestV, bestDist = version, v, dist
+		}
+	}
+	return best, best != ""
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// MCP elicitation
+//
+// 工具调用遇到需要用户决定的歧义（多个相近的候选库、请求的版本不存在）时，
+// 对声明了 elicitation 能力的客户端发送 elicitation/create，请用户从列表中选择，
+// 然后用选择的值继续原来的工具调用。客户端不支持、用户拒绝或超时时按原逻辑处理
+
+// Elicitation 用户响应动作
+const (
+	ElicitActionAccept  = "accept"
+	ElicitActionDecline = "decline"
+	ElicitActionCancel  = "cancel"
+)
+
+// elicitationTimeout 等待用户响应的最长时间
+const elicitationTimeout = 5 * time.Minute
+
+// MCPElicitResult elicitation/create 的用户响应
+type MCPElicitResult struct {
+	Action  string                 // accept、decline、cancel
+	Content map[string]interface{} // accept 时用户填写的内容
+}
+
+// MCPElicitor 在工具调用过程中向客户端发起 elicitation/create
+// nil 表示客户端不支持，所有方法对 nil 安全
+type MCPElicitor struct {
+	scope  string
+	writer transport.RequestWriter
+}
+
+// NewMCPElicitor 创建 elicitor（客户端未声明 elicitation 能力、协议版本过低或写入器不支持服务端请求时返回 nil）
+func NewMCPElicitor(req *transport.RequestContext, client *transport.ClientInfo, writer transport.ResponseWriter) *MCPElicitor {
+	if client == nil || !supportsElicitation(client.ProtocolVersion) {
+		return nil
+	}
+	if _, ok := client.Capabilities["elicitation"]; !ok {
+		return nil
+	}
+	requestWriter, ok := writer.(transport.RequestWriter)
+	if !ok {
+		return nil
+	}
+	scope := requestScope(req)
+	if scope == "" {
+		return nil
+	}
+	return &MCPElicitor{scope: scope, writer: requestWriter}
+}
+
+// Elicit 发送 elicitation/create 并等待用户响应
+// requestedSchema 为扁平的 object schema（属性只能是 string/number/boolean/enum）
+func (e *MCPElicitor) Elicit(ctx context.Context, message string, requestedSchema map[string]interface{}) (*MCPElicitResult, error) {
+	if e == nil {
+		return nil, ErrElicitationUnavailable
+	}
+
+	id := transport.NewServerRequestID()
+	responses, done := transport.AwaitResponse(ctx, e.scope, id)
+	defer done()
+
+	req := response.NewMCPServerRequest(id, "elicitation/create", map[string]interface{}{
+		"message":         message,
+		"requestedSchema": requestedSchema,
+	})
+	if err := e.writer.WriteRequest(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrElicitationUnavailable, err)
+	}
+
+	timer := time.NewTimer(elicitationTimeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-responses:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%w: %s", ErrElicitationUnavailable, resp.Error.Message)
+		}
+		result, _ := resp.Result.(map[string]interface{})
+		action, _ := result["action"].(string)
+		content, _ := result["content"].(map[string]interface{})
+		return &MCPElicitResult{Action: action, Content: content}, nil
+	case <-timer.C:
+		return &MCPElicitResult{Action: ElicitActionCancel}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ElicitChoice 请用户从选项中选择一项，返回选择的值（用户未选择时 ok 为 false）
+func (e *MCPElicitor) ElicitChoice(ctx context.Context, message, field, title string, options, labels []string) (string, bool, error) {
+	property := map[string]interface{}{
+		"type":  "string",
+		"title": title,
+		"enum":  options,
+	}
+	if len(labels) == len(options) {
+		property["enumNames"] = labels
+	}
+
+	result, err := e.Elicit(ctx, message, map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{field: property},
+		"required":   []string{field},
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if result.Action != ElicitActionAccept {
+		return "", false, nil
+	}
+	choice, _ := result.Content[field].(string)
+	for _, option := range options {
+		if option == choice {
+			return choice, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// mcpElicitorKey context 中存放 MCPElicitor 的 key
+type mcpElicitorKey struct{}
+
+// WithMCPElicitor 将 elicitor 放入 context，供工具在遇到歧义时询问用户
+func WithMCPElicitor(ctx context.Context, elicitor *MCPElicitor) context.Context {
+	if elicitor == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, mcpElicitorKey{}, elicitor)
+}
+
+// mcpElicitor 获取 context 中的 elicitor（没有时返回 nil）
+func mcpElicitor(ctx context.Context) *MCPElicitor {
+	e, _ := ctx.Value(mcpElicitorKey{}).(*MCPElicitor)
+	return e
+}
+
+// ProcessResponse 处理客户端对服务端请求的响应（交给本实例或经 Redis 交给其他实例上等待中的 elicitation），没有等待者时返回 false
+func (h *MCPHandler) ProcessResponse(req *transport.RequestContext, resp *response.MCPResponse) bool {
+	scope := requestScope(req)
+	delivered := scope != "" && transport.DeliverResponse(req.Context(), scope, resp)
+	if !delivered {
+		global.Log.Debug("收到无对应请求的客户端响应", zap.Any("id", resp.ID), zap.String("transport", string(req.Transport)))
+	}
+	return delivered
+}
@@ -1,11 +1,13 @@
 package service
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/model/response"
 	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/session"
 	"go-mcp-context/pkg/global"
 	"net/url"
 	"strings"
@@ -16,13 +18,15 @@ import (
 // MCPHandler MCP统一处理器
 // 负责协议无关的业务逻辑处理，调用MCPService执行具体业务
 type MCPHandler struct {
-	mcpService *MCPService
+	mcpService    *MCPService
+	promptService *PromptService
 }
 
 // NewMCPHandler 创建MCP处理器
 func NewMCPHandler() *MCPHandler {
 	return &MCPHandler{
-		mcpService: NewMCPService(),
+		mcpService:    NewMCPService(),
+		promptService: &PromptService{},
 	}
 }
 
@@ -34,6 +38,18 @@ func (h *MCPHandler) ProcessRequest(req *transport.RequestContext, writer transp
 		zap.String("method", req.Method),
 	)
 
+	// 登记进行中的请求，供 notifications/cancelled 按请求ID取消（无状态 HTTP 请求只随连接断开取消）
+	if scope := requestScope(req); req.ID != nil && scope != "" {
+		ctx, done := transport.TrackRequest(req.Context(), scope, req.ID)
+		defer done()
+		req.Ctx = ctx
+	}
+
+	// 加载会话中协商的客户端信息（调用日志按客户端统计）
+	if req.Method != "initialize" {
+		h.resolveClient(req)
+	}
+
 	switch req.Method {
 	case "initialize":
 		return h.handleInitialize(req, writer)
@@ -41,6 +57,9 @@ func (h *MCPHandler) ProcessRequest(req *transport.RequestContext, writer transp
 	case "notifications/initialized", "initialized":
 		return h.handleInitialized(req, writer)
 
+	case "notifications/cancelled":
+		return h.handleCancelled(req, writer)
+
 	case "tools/list":
 		return h.handleToolsList(req, writer)
 
@@ -56,6 +75,24 @@ func (h *MCPHandler) ProcessRequest(req *transport.RequestContext, writer transp
 	case "resources/read":
 		return h.handleResourcesRead(req, writer)
 
+	case "resources/subscribe":
+		return h.handleResourcesSubscribe(req, writer)
+
+	case "resources/unsubscribe":
+		return h.handleResourcesUnsubscribe(req, writer)
+
+	case "prompts/list":
+		return h.handlePromptsList(req, writer)
+
+	case "prompts/get":
+		return h.handlePromptsGet(req, writer)
+
+	case "logging/setLevel":
+		return h.handleLoggingSetLevel(req, writer)
+
+	case "completion/complete":
+		return h.handleCompletionComplete(req, writer)
+
 	default:
 		// 未知方法
 		return writer.WriteError(&response.MCPError{
@@ -66,27 +103,65 @@ func (h *MCPHandler) ProcessRequest(req *transport.RequestContext, writer transp
 }
 
 // handleInitialize 处理initialize请求
+// 协商协议版本，并按协商版本返回服务端能力
 func (h *MCPHandler) handleInitialize(req *transport.RequestContext, writer transport.ResponseWriter) error {
-	result := map[string]interface{}{
-		"protocolVersion": "2025-11-25",
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{
-				"listChanged": true,
-			},
-			"resources": map[string]interface{}{
-				"subscribe":   true,
-				"listChanged": true,
+	requested, _ := req.Params["protocolVersion"].(string)
+	protocolVersion, err := NegotiateProtocolVersion(requested)
+	if err != nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32602,
+			Message: "Unsupported protocol version",
+			Data: map[string]interface{}{
+				"supported": SupportedProtocolVersions,
+				"requested": requested,
 			},
-			"logging": map[string]interface{}{},
+		}, req.ID)
+	}
+
+	capabilities := map[string]interface{}{
+		"tools": map[string]interface{}{
+			"listChanged": true,
 		},
+		"resources": map[string]interface{}{
+			"subscribe":   true,
+			"listChanged": true,
+		},
+		"prompts": map[string]interface{}{
+			"listChanged": true,
+		},
+		"logging": map[string]interface{}{},
+	}
+	if supportsCompletions(protocolVersion) {
+		capabilities["completions"] = map[string]interface{}{}
+	}
+
+	result := map[string]interface{}{
+		"protocolVersion": protocolVersion,
+		"capabilities":    capabilities,
 		"serverInfo": map[string]interface{}{
 			"name":    "go-mcp-context",
 			"version": "1.0.0",
 		},
 	}
 
+	// Streamable HTTP: 创建会话并通过 Mcp-Session-Id 响应头下发
+	if sessionWriter, ok := writer.(transport.SessionWriter); ok {
+		if store := session.GetStore(); store != nil {
+			sess, err := store.Create(context.Background(), req.UserID)
+			if err != nil {
+				global.Log.Warn("创建MCP会话失败", zap.Error(err))
+			} else {
+				req.SessionID = sess.ID
+				sessionWriter.SetSessionID(sess.ID)
+			}
+		}
+	}
+
+	// 保存协商结果，后续请求据此启用对应版本的特性
+	h.saveClient(context.Background(), req, parseClientInfo(req.Params, protocolVersion))
+
 	// 统计结果数量（initialize返回的是capabilities和serverInfo，计为1）
-	req.GinCtx.Set("mcp_result_count", 1)
+	req.Set("mcp_result_count", 1)
 
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
@@ -104,215 +179,460 @@ func (h *MCPHandler) handleInitialized(req *transport.RequestContext, writer tra
 	return nil
 }
 
-// handleToolsList 处理tools/list请求
-func (h *MCPHandler) handleToolsList(req *transport.RequestContext, writer transport.ResponseWriter) error {
-	tools := []map[string]interface{}{
-		{
-			"name":        "search-libraries",
-			"description": "Search for documentation libraries by name using semantic vector search (primary) with fuzzy matching fallback. Returns matching libraries with metadata including available versions. Use this method to discover libraries and get their version information (versions array and defaultVersion) before calling get-library-docs.",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"libraryName": map[string]interface{}{
-						"type":        "string",
-						"description": "The name of the library to search for",
-					},
-				},
-				"required": []string{"libraryName"},
-			},
-		},
-		{
-			"name":        "get-library-docs",
-			"description": "Get documentation for a specific library. Requires libraryId, topic, and version. Supports comma-separated topics for multi-topic search.",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"libraryId": map[string]interface{}{
-						"type":        "integer",
-						"description": "Library ID (required)",
-					},
-					"topic": map[string]interface{}{
-						"type":        "string",
-						"description": "Documentation topic (required, supports comma-separated topics like 'overview,api,examples')",
-					},
-					"version": map[string]interface{}{
-						"type":        "string",
-						"description": "Library version (required)",
-					},
-					"mode": map[string]interface{}{
-						"type":        "string",
-						"description": "Search mode: info or code",
-						"enum":        []string{"info", "code"},
-					},
-					"page": map[string]interface{}{
-						"type":        "integer",
-						"description": "Page number (1-10)",
-					},
-				},
-				"required": []string{"libraryId", "topic", "version"},
-			},
-		},
+// handleCancelled 处理notifications/cancelled通知
+// 取消同一会话中仍在进行的请求，被取消的请求不再返回响应；
+// 无会话的 HTTP 请求之间无法区分发起方（同一 API Key 的多个客户端会复用请求ID），通知被忽略
+func (h *MCPHandler) handleCancelled(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	requestID := req.Params["requestId"]
+	scope := requestScope(req)
+	if requestID == nil || scope == "" {
+		return nil
+	}
+
+	reason, _ := req.Params["reason"].(string)
+	cancelled := transport.CancelRequest(req.Context(), scope, requestID)
+	global.Log.Info("收到MCP请求取消通知",
+		zap.Any("request_id", requestID),
+		zap.String("reason", reason),
+		zap.Bool("cancelled_locally", cancelled),
+	)
+	return nil
+}
+
+// requestScope 请求ID的作用域
+// 会话请求为会话ID；stdio 进程即一条连接，无会话时为调用者ID；
+// 无会话的 HTTP 请求返回空，不参与取消与服务端请求的响应关联
+func requestScope(req *transport.RequestContext) string {
+	if req.SessionID != "" {
+		return req.SessionID
+	}
+	if req.Transport == transport.TransportStdio {
+		return req.UserID
 	}
+	return ""
+}
 
-	// 统计结果数量（返回2个工具）
-	req.GinCtx.Set("mcp_result_count", len(tools))
+// handleResourcesSubscribe 处理resources/subscribe请求
+// 订阅后资源对应的库/版本文档变化时推送 notifications/resources/updated
+func (h *MCPHandler) handleResourcesSubscribe(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	return h.handleResourceSubscription(req, writer, subscribeResource)
+}
+
+// handleResourcesUnsubscribe 处理resources/unsubscribe请求
+func (h *MCPHandler) handleResourcesUnsubscribe(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	return h.handleResourceSubscription(req, writer, unsubscribeResource)
+}
+
+// handleResourceSubscription 订阅/取消订阅的公共处理
+func (h *MCPHandler) handleResourceSubscription(req *transport.RequestContext, writer transport.ResponseWriter,
+	apply func(ctx context.Context, store *session.Store, sessionID, uri string, ref *resourceRef) error) error {
+	uri, _ := req.Params["uri"].(string)
+	if uri == "" {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32602,
+			Message: "Missing or invalid uri parameter",
+		}, req.ID)
+	}
+
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32602,
+			Message: "Invalid params: " + err.Error(),
+		}, req.ID)
+	}
+
+	// 变更通知需要推送通道，无状态请求无法接收
+	store := session.GetStore()
+	if req.SessionID == "" || store == nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32600,
+			Message: req.Method + " requires a session",
+		}, req.ID)
+	}
+
+	if err := apply(req.Context(), store, req.SessionID, uri, ref); err != nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32603,
+			Message: "Internal error: " + err.Error(),
+		}, req.ID)
+	}
+
+	req.Set("mcp_result_count", 1)
+	req.Set("mcp_library_id", ref.LibraryID)
 
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
-		Result:  map[string]interface{}{"tools": tools},
+		Result:  map[string]interface{}{},
 	}
 
 	return writer.WriteResponse(resp)
 }
 
-// handleToolsCall 处理tools/call请求
-func (h *MCPHandler) handleToolsCall(req *transport.RequestContext, writer transport.ResponseWriter) error {
-	// 提取工具名称
-	toolName, ok := req.Params["name"].(string)
-	if !ok {
+// handlePromptsList 处理prompts/list请求
+func (h *MCPHandler) handlePromptsList(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	prompts, err := h.promptService.ListMCPPrompts()
+	if err != nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32603,
+			Message: "Internal error: " + err.Error(),
+		}, req.ID)
+	}
+
+	req.Set("mcp_result_count", len(prompts))
+
+	resp := &response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  map[string]interface{}{"prompts": prompts},
+	}
+
+	return writer.WriteResponse(resp)
+}
+
+// handlePromptsGet 处理prompts/get请求
+// 按模板填充参数，并检索库文档填充到提示中
+func (h *MCPHandler) handlePromptsGet(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	name, _ := req.Params["name"].(string)
+	if name == "" {
 		return writer.WriteError(&response.MCPError{
 			Code:    -32602,
-			Message: "Invalid params: missing tool name",
+			Message: "Invalid params: missing prompt name",
 		}, req.ID)
 	}
 
-	// 提取工具参数
-	arguments, _ := req.Params["arguments"].(map[string]interface{})
+	// 参数值统一转为字符串
+	args := make(map[string]string)
+	if rawArgs, ok := req.Params["arguments"].(map[string]interface{}); ok {
+		for k, v := range rawArgs {
+			if v != nil {
+				args[k] = fmt.Sprint(v)
+			}
+		}
+	}
 
-	// 根据工具名称分发
-	switch toolName {
-	case "search-libraries":
-		return h.handleSearchLibraries(arguments, req, writer)
+	result, err := h.promptService.GetMCPPrompt(h.loggerContext(req), name, args)
+	if err != nil {
+		// 版本不存在时错误信息中包含可用版本列表
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalidParams) || errors.Is(err, ErrVersionNotFound) {
+			return writer.WriteError(&response.MCPError{
+				Code:    -32602,
+				Message: "Invalid params: " + err.Error(),
+			}, req.ID)
+		}
+		return writer.WriteError(&response.MCPError{
+			Code:    -32603,
+			Message: "Internal error: " + err.Error(),
+		}, req.ID)
+	}
 
-	case "get-library-docs":
-		return h.handleGetLibraryDocs(arguments, req, writer)
+	req.Set("mcp_result_count", len(result.Messages))
 
-	default:
+	resp := &response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+
+	return writer.WriteResponse(resp)
+}
+
+// handleLoggingSetLevel 处理logging/setLevel请求
+// 日志级别保存在会话属性中，之后该会话的调用按级别推送 notifications/message
+func (h *MCPHandler) handleLoggingSetLevel(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	level, _ := req.Params["level"].(string)
+	if !IsValidMCPLogLevel(level) {
 		return writer.WriteError(&response.MCPError{
 			Code:    -32602,
-			Message: "Unknown tool: " + toolName,
+			Message: "Invalid params: unknown log level " + level,
+		}, req.ID)
+	}
+
+	// 日志通知需要推送通道，无状态请求无法接收
+	store := session.GetStore()
+	if req.SessionID == "" || store == nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32600,
+			Message: "logging/setLevel requires a session",
+		}, req.ID)
+	}
+
+	if err := store.SetAttr(context.Background(), req.SessionID, session.AttrLogLevel, level); err != nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32603,
+			Message: "Internal error: " + err.Error(),
 		}, req.ID)
 	}
+
+	req.Set("mcp_result_count", 1)
+
+	resp := &response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  map[string]interface{}{},
+	}
+
+	return writer.WriteResponse(resp)
 }
 
-// handleSearchLibraries 处理search-libraries工具调用
-func (h *MCPHandler) handleSearchLibraries(args map[string]interface{}, req *transport.RequestContext, writer transport.ResponseWriter) error {
-	// 提取参数
-	libraryName, _ := args["libraryName"].(string)
-	if libraryName == "" {
+// handleCompletionComplete 处理completion/complete请求
+// 为提示参数和资源模板参数（libraryId、version、topic 等）提供自动补全候选
+func (h *MCPHandler) handleCompletionComplete(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	completeReq := &request.MCPComplete{}
+	if ref, ok := req.Params["ref"].(map[string]interface{}); ok {
+		completeReq.Ref.Type, _ = ref["type"].(string)
+		completeReq.Ref.Name, _ = ref["name"].(string)
+		completeReq.Ref.URI, _ = ref["uri"].(string)
+	}
+	if argument, ok := req.Params["argument"].(map[string]interface{}); ok {
+		completeReq.ArgumentName, _ = argument["name"].(string)
+		completeReq.ArgumentValue, _ = argument["value"].(string)
+	}
+	if completeReq.ArgumentName == "" {
 		return writer.WriteError(&response.MCPError{
 			Code:    -32602,
-			Message: "Invalid params: libraryName is required",
+			Message: "Invalid params: missing argument name",
 		}, req.ID)
 	}
 
-	// 调用service层
-	searchReq := &request.MCPSearchLibraries{LibraryName: libraryName}
-	result, err := h.mcpService.SearchLibraries(searchReq)
+	// 已填写的其他参数，用于确定版本、主题所属的库
+	completeReq.Arguments = make(map[string]string)
+	if completionCtx, ok := req.Params["context"].(map[string]interface{}); ok {
+		if rawArgs, ok := completionCtx["arguments"].(map[string]interface{}); ok {
+			for k, v := range rawArgs {
+				if v != nil {
+					completeReq.Arguments[k] = fmt.Sprint(v)
+				}
+			}
+		}
+	}
+
+	result, err := h.mcpService.Complete(h.loggerContext(req), completeReq)
 	if err != nil {
+		if errors.Is(err, ErrInvalidParams) {
+			return writer.WriteError(&response.MCPError{
+				Code:    -32602,
+				Message: "Invalid params: " + err.Error(),
+			}, req.ID)
+		}
 		return writer.WriteError(&response.MCPError{
 			Code:    -32603,
 			Message: "Internal error: " + err.Error(),
 		}, req.ID)
 	}
 
-	// 设置结果信息到context，供中间件记录日志
-	req.GinCtx.Set("mcp_result_count", len(result.Libraries))
+	req.Set("mcp_result_count", len(result.Completion.Values))
 
-	// 转换为MCP规范的格式
-	// MCP期望的格式: { content: [{ type: "text", text: "..." }] }
-	resultJSON, _ := json.Marshal(result)
-	mcpResult := map[string]interface{}{
-		"content": []map[string]interface{}{
-			{
-				"type": "text",
-				"text": string(resultJSON),
-			},
-		},
+	resp := &response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+
+	return writer.WriteResponse(resp)
+}
+
+// loggerContext 构造携带会话日志记录器的 context
+func (h *MCPHandler) loggerContext(req *transport.RequestContext) context.Context {
+	ctx := req.Context()
+	return WithMCPLogger(ctx, NewMCPLogger(ctx, req.SessionID))
+}
+
+// toolContext 构造工具调用的 context（会话日志 + 请求携带 progressToken 时的进度报告 + 客户端支持时的 elicitation）
+func (h *MCPHandler) toolContext(req *transport.RequestContext, writer transport.ResponseWriter) context.Context {
+	ctx := h.loggerContext(req)
+	ctx = WithMCPProgress(ctx, NewMCPProgress(request.ProgressToken(req.Params), writer))
+	return WithMCPElicitor(ctx, NewMCPElicitor(req, h.resolveClient(req), writer))
+}
+
+// handleToolsList 处理tools/list请求
+func (h *MCPHandler) handleToolsList(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	// outputSchema 自 2025-06-18 起支持
+	withOutputSchema := supportsStructuredContent(h.clientProtocolVersion(req))
+
+	registered := MCPTools.List()
+	tools := make([]map[string]interface{}, 0, len(registered))
+	for _, tool := range registered {
+		definition := map[string]interface{}{
+			"name":        tool.Name(),
+			"description": tool.Description(),
+			"inputSchema": tool.InputSchema(),
+		}
+		if outputSchema := tool.OutputSchema(); withOutputSchema && outputSchema != nil {
+			definition["outputSchema"] = outputSchema
+		}
+		tools = append(tools, definition)
 	}
 
+	// 统计结果数量（返回的工具数）
+	req.Set("mcp_result_count", len(tools))
+
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
-		Result:  mcpResult,
+		Result:  map[string]interface{}{"tools": tools},
 	}
 
 	return writer.WriteResponse(resp)
 }
 
-// handleGetLibraryDocs 处理get-library-docs工具调用
-func (h *MCPHandler) handleGetLibraryDocs(args map[string]interface{}, req *transport.RequestContext, writer transport.ResponseWriter) error {
-	// 提取参数
-	var libraryID uint
-	if id, ok := args["libraryId"].(float64); ok {
-		libraryID = uint(id)
-	}
-	version, _ := args["version"].(string)
-	topic, _ := args["topic"].(string)
-	mode, _ := args["mode"].(string)
-	page := 1
-	if p, ok := args["page"].(float64); ok {
-		page = int(p)
+// handleToolsCall 处理tools/call请求
+// 从工具注册表查找工具，按 inputSchema 校验参数后执行
+func (h *MCPHandler) handleToolsCall(req *transport.RequestContext, writer transport.ResponseWriter) error {
+	// 提取工具名称
+	toolName, ok := req.Params["name"].(string)
+	if !ok {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32602,
+			Message: "Invalid params: missing tool name",
+		}, req.ID)
 	}
 
-	// 参数验证
-	if topic == "" {
+	tool, ok := MCPTools.Get(toolName)
+	if !ok {
 		return writer.WriteError(&response.MCPError{
 			Code:    -32602,
-			Message: "Invalid params: topic is required",
+			Message: "Unknown tool: " + toolName,
 		}, req.ID)
 	}
 
-	// 调用service层
-	docsReq := &request.MCPGetLibraryDocs{
-		LibraryID: libraryID,
-		Topic:     topic,
-		Version:   version,
-		Mode:      mode,
-		Page:      page,
+	// 提取并校验工具参数
+	arguments, _ := req.Params["arguments"].(map[string]interface{})
+	if arguments == nil {
+		arguments = map[string]interface{}{}
 	}
-	result, err := h.mcpService.GetLibraryDocs(docsReq)
-	if err != nil {
+	if err := validateToolArguments(tool.InputSchema(), arguments); err != nil {
 		return writer.WriteError(&response.MCPError{
-			Code:    -32603,
-			Message: "Internal error: " + err.Error(),
+			Code:    -32602,
+			Message: "Invalid params: " + err.Error(),
 		}, req.ID)
 	}
 
+	result, err := tool.Call(h.toolContext(req, writer), arguments)
+	if err != nil {
+		var paramsErr *ToolParamsError
+		if errors.As(err, &paramsErr) {
+			return writer.WriteError(&response.MCPError{
+				Code:    -32602,
+				Message: "Invalid params: " + paramsErr.Message,
+			}, req.ID)
+		}
+		return h.writeToolError(req, writer, toolName, err)
+	}
+
+	// 工具执行期间请求已取消，不再返回结果
+	if h.toolCallCancelled(req, toolName, nil) {
+		return nil
+	}
+
 	// 设置结果信息到context，供中间件记录日志
-	req.GinCtx.Set("mcp_result_count", len(result.Documents))
+	req.Set("mcp_result_count", result.ResultCount)
 	if result.LibraryID > 0 {
-		req.GinCtx.Set("mcp_library_id", result.LibraryID)
+		req.Set("mcp_library_id", result.LibraryID)
 	}
 
-	// 转换为MCP规范的格式
-	// MCP期望的格式: { content: [{ type: "text", text: "..." }] }
-	resultJSON, _ := json.Marshal(result)
-	mcpResult := map[string]interface{}{
+	// 转换为MCP规范的格式：Markdown 文本供模型阅读，structuredContent 供程序解析
+	resp := &response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  h.newToolResult(req, result.Text, result.Structured),
+	}
+
+	return writer.WriteResponse(resp)
+}
+
+// newToolResult 构造工具调用结果
+// text 为结构化结果的 Markdown 渲染，两者描述同一份数据；协商版本早于 2025-06-18 时只返回文本
+func (h *MCPHandler) newToolResult(req *transport.RequestContext, text string, structured interface{}) map[string]interface{} {
+	result := map[string]interface{}{
 		"content": []map[string]interface{}{
 			{
 				"type": "text",
-				"text": string(resultJSON),
+				"text": text,
 			},
 		},
 	}
+	if structured != nil && supportsStructuredContent(h.clientProtocolVersion(req)) {
+		result["structuredContent"] = structured
+	}
+	return result
+}
+
+// writeToolError 以 isError 结果返回工具执行失败（请求已取消时不返回）
+// 协议错误（未知工具、参数缺失）仍使用 JSON-RPC error；执行失败需要让模型看到原因并自行修正，
+// 因此作为工具结果返回，同时写入 mcp_tool_error 供中间件记录为失败调用
+func (h *MCPHandler) writeToolError(req *transport.RequestContext, writer transport.ResponseWriter, toolName string, err error) error {
+	if h.toolCallCancelled(req, toolName, err) {
+		return nil
+	}
+
+	global.Log.Warn("MCP工具执行失败", zap.String("tool", toolName), zap.Error(err))
+	req.Set("mcp_tool_error", err.Error())
 
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
-		Result:  mcpResult,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": toolErrorGuidance(toolName, err),
+				},
+			},
+			"isError": true,
+		},
 	}
 
 	return writer.WriteResponse(resp)
 }
 
+// toolCallCancelled 请求已取消（客户端断开或 notifications/cancelled）时记录为取消，按规范不再返回响应
+func (h *MCPHandler) toolCallCancelled(req *transport.RequestContext, toolName string, err error) bool {
+	if req.Context().Err() == nil {
+		return false
+	}
+	global.Log.Info("MCP工具调用已取消", zap.String("tool", toolName), zap.Error(err))
+	req.Set("mcp_tool_error", "cancelled")
+	return true
+}
+
+// toolErrorGuidance 根据服务层错误生成面向模型的修正建议
+func toolErrorGuidance(toolName string, err error) string {
+	var versionErr *VersionNotFoundError
+	switch {
+	case errors.As(err, &versionErr):
+		return fmt.Sprintf("Version %q not found. Available versions: %s. Retry %s with one of these versions.",
+			versionErr.Version, strings.Join(versionErr.Available, ", "), toolName)
+	case errors.Is(err, ErrNotFound):
+		return fmt.Sprintf("%s failed: no library matches the given libraryId. Call search-libraries to find a valid libraryId, then retry.", toolName)
+	case errors.Is(err, ErrEmbeddingUnavailable):
+		return fmt.Sprintf("%s failed: the semantic search backend is temporarily unavailable. Retry in a few seconds; if it keeps failing, report the outage to the user.", toolName)
+	case errors.Is(err, ErrInvalidParams):
+		return fmt.Sprintf("%s failed: invalid arguments (%v). Check the tool's inputSchema and retry.", toolName, err)
+	default:
+		return fmt.Sprintf("%s failed: %v", toolName, err)
+	}
+}
+
 // handleResourcesList 处理resources/list请求
-// 从数据库查询所有可用的库，动态生成资源列表
+// 从数据库分页查询可用的库，动态生成资源列表
+// 扩展参数 filter.access 可只列出调用者有权访问的库（accessible/owned）
 func (h *MCPHandler) handleResourcesList(req *transport.RequestContext, writer transport.ResponseWriter) error {
-	// 从数据库查询所有库
-	libraries, err := h.mcpService.GetAllLibraries()
+	listReq := &request.MCPListResources{UserID: req.UserID}
+	listReq.Cursor, _ = req.Params["cursor"].(string)
+	if filter, ok := req.Params["filter"].(map[string]interface{}); ok {
+		listReq.Access, _ = filter["access"].(string)
+	}
+
+	libraries, nextCursor, err := h.mcpService.ListLibrariesPage(req.Context(), listReq)
 	if err != nil {
+		if errors.Is(err, ErrInvalidParams) {
+			return writer.WriteError(&response.MCPError{
+				Code:    -32602,
+				Message: "Invalid params: " + err.Error(),
+			}, req.ID)
+		}
 		return writer.WriteError(&response.MCPError{
 			Code:    -32603,
 			Message: "Internal error: " + err.Error(),
@@ -331,12 +651,17 @@ func (h *MCPHandler) handleResourcesList(req *transport.RequestContext, writer t
 	}
 
 	// 统计结果数量
-	req.GinCtx.Set("mcp_result_count", len(resources))
+	req.Set("mcp_result_count", len(resources))
+
+	result := map[string]interface{}{"resources": resources}
+	if nextCursor != "" {
+		result["nextCursor"] = nextCursor
+	}
 
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
-		Result:  map[string]interface{}{"resources": resources},
+		Result:  result,
 	}
 
 	return writer.WriteResponse(resp)
@@ -361,12 +686,29 @@ func (h *MCPHandler) handleResourceTemplatesList(req *transport.RequestContext,
 		},
 	}
 
+	// 分页（游标为空表示第一页）
+	cursor, _ := req.Params["cursor"].(string)
+	start, end, nextCursor, err := pageResourceTemplates(cursor, len(templates))
+	if err != nil {
+		return writer.WriteError(&response.MCPError{
+			Code:    -32602,
+			Message: "Invalid params: " + err.Error(),
+		}, req.ID)
+	}
+	templates = templates[start:end]
+
 	// 统计结果数量
-	req.GinCtx.Set("mcp_result_count", len(templates))
+	req.Set("mcp_result_count", len(templates))
+
+	result := map[string]interface{}{"resourceTemplates": templates}
+	if nextCursor != "" {
+		result["nextCursor"] = nextCursor
+	}
+
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
-		Result:  map[string]interface{}{"resourceTemplates": templates},
+		Result:  result,
 	}
 
 	return writer.WriteResponse(resp)
@@ -499,7 +841,7 @@ func (h *MCPHandler) handleReadLibraryResource(segments []string, uri string, re
 	}
 
 	// 统计结果数量（返回1个资源）
-	req.GinCtx.Set("mcp_result_count", 1)
+	req.Set("mcp_result_count", 1)
 
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
@@ -571,7 +913,12 @@ func (h *MCPHandler) handleReadDocsResource(segments []string, uri string, req *
 		Version:   version,
 	}
 
-	result, err := h.mcpService.GetLibraryDocs(tempReq)
+	result, err := h.mcpService.GetLibraryDocsWithContext(h.loggerContext(req), tempReq)
+	if req.Context().Err() != nil {
+		// 请求已取消（客户端断开或 notifications/cancelled），按规范不再返回响应
+		global.Log.Info("MCP资源读取已取消", zap.String("uri", uri), zap.Error(err))
+		return nil
+	}
 	if err != nil {
 		return writer.WriteError(&response.MCPError{
 			Code:    -32603,
@@ -599,7 +946,7 @@ func (h *MCPHandler) handleReadDocsResource(segments []string, uri string, req *
 	}
 
 	// 统计结果数量
-	req.GinCtx.Set("mcp_result_count", len(contents))
+	req.Set("mcp_result_count", len(contents))
 
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// MCP logging 能力
+//
+// 客户端通过 logging/setLevel 为会话设置日志级别，之后该会话的工具调用
+// 会以 notifications/message 推送结构化日志（缓存命中、降级、候选数量等）。
+// 未设置级别的会话不推送，避免打扰不关心日志的客户端。
+
+// mcpLogLevels MCP 日志级别（RFC 5424，数值越大越严重）
+var mcpLogLevels = map[string]int{
+	"debug":     0,
+	"info":      1,
+	"notice":    2,
+	"warning":   3,
+	"error":     4,
+	"critical":  5,
+	"alert":     6,
+	"emergency": 7,
+}
+
+// IsValidMCPLogLevel 判断是否为合法的 MCP 日志级别
+func IsValidMCPLogLevel(level string) bool {
+	_, ok := mcpLogLevels[level]
+	return ok
+}
+
+// MCPLogger 向会话推送 notifications/message 的日志记录器
+// nil 表示不推送，所有方法对 nil 安全
+type MCPLogger struct {
+	sessionID string
+	minLevel  int
+}
+
+// NewMCPLogger 根据会话设置的日志级别创建记录器（未设置或无会话时返回 nil）
+func NewMCPLogger(ctx context.Context, sessionID string) *MCPLogger {
+	if sessionID == "" {
+		return nil
+	}
+	store := session.GetStore()
+	if store == nil {
+		return nil
+	}
+
+	level, err := store.GetAttr(ctx, sessionID, session.AttrLogLevel)
+	if err != nil || level == "" {
+		return nil
+	}
+	minLevel, ok := mcpLogLevels[level]
+	if !ok {
+		return nil
+	}
+
+	return &MCPLogger{sessionID: sessionID, minLevel: minLevel}
+}
+
+// Log 推送一条日志（低于会话级别的日志丢弃）
+func (l *MCPLogger) Log(level, logger string, data interface{}) {
+	if l == nil {
+		return
+	}
+	if severity, ok := mcpLogLevels[level]; !ok || severity < l.minLevel {
+		return
+	}
+
+	notification := response.NewMCPNotification("notifications/message", map[string]interface{}{
+		"level":  level,
+		"logger": logger,
+		"data":   data,
+	})
+	if err := transport.Push(context.Background(), l.sessionID, notification); err != nil {
+		global.Log.Debug("推送MCP日志失败", zap.String("session_id", l.sessionID), zap.Error(err))
+	}
+}
+
+// mcpLoggerKey context 中存放 MCPLogger 的 key
+type mcpLoggerKey struct{}
+
+// WithMCPLogger 将日志记录器放入 context，供 service 各层推送日志
+func WithMCPLogger(ctx context.Context, logger *MCPLogger) context.Context {
+	if logger == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, mcpLoggerKey{}, logger)
+}
+
+// mcpLog 通过 context 中的记录器推送日志（没有记录器时忽略）
+func mcpLog(ctx context.Context, level, logger string, data interface{}) {
+	l, _ := ctx.Value(mcpLoggerKey{}).(*MCPLogger)
+	l.Log(level, logger, data)
+}
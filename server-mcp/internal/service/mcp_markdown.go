@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"go-mcp-context/internal/model/response"
+)
+
+// renderDocumentsMarkdown 将文档片段渲染为 Markdown（每个片段一个小节）
+func renderDocumentsMarkdown(docs []response.MCPDocumentChunk) string {
+	var b strings.Builder
+	for i, doc := range docs {
+		if i > 0 {
+			b.WriteString("\n---\n\n")
+		}
+
+		title := doc.Title
+		if title == "" {
+			title = doc.Source
+		}
+		fmt.Fprintf(&b, "### %s\n\n", title)
+
+		if doc.Description != "" {
+			b.WriteString(strings.TrimSpace(doc.Description) + "\n\n")
+		}
+		if doc.Code != "" {
+			fence := codeFence(doc.Code)
+			fmt.Fprintf(&b, "%s%s\n%s\n%s\n\n", fence, doc.Language, strings.TrimRight(doc.Code, "\n"), fence)
+		}
+		if doc.Content != "" {
+			b.WriteString(strings.TrimSpace(doc.Content) + "\n\n")
+		}
+		if doc.Truncated {
+			b.WriteString("_(truncated to fit the token budget)_\n\n")
+		}
+
+		fmt.Fprintf(&b, "Source: %s (version %s)\n", sourceLink(doc.Source), doc.Version)
+	}
+	return b.String()
+}
+
+// renderLibrariesMarkdown 将 search-libraries 结果渲染为 Markdown（字段与 structuredContent 一致）
+func renderLibrariesMarkdown(result *response.MCPSearchLibrariesResult) string {
+	if len(result.Libraries) == 0 {
+		return "No libraries found.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d libraries. Pass `libraryId` and a version to get-library-docs.\n", len(result.Libraries))
+	for _, lib := range result.Libraries {
+		fmt.Fprintf(&b, "\n### %s (libraryId: %d)\n\n", lib.Name, lib.LibraryID)
+		if lib.Description != "" {
+			b.WriteString(strings.TrimSpace(lib.Description) + "\n\n")
+		}
+		fmt.Fprintf(&b, "- Versions: %s\n", strings.Join(lib.Versions, ", "))
+		fmt.Fprintf(&b, "- Default version: %s\n", lib.DefaultVersion)
+		fmt.Fprintf(&b, "- Snippets: %d\n", lib.Snippets)
+		fmt.Fprintf(&b, "- Score: %.2f\n", lib.Score)
+	}
+	return b.String()
+}
+
+// renderLibraryDocsMarkdown 将 get-library-docs 结果渲染为 Markdown（字段与 structuredContent 一致）
+func renderLibraryDocsMarkdown(result *response.MCPGetLibraryDocsResult) string {
+	if len(result.Documents) == 0 {
+		return "No documentation found for this topic.\n"
+	}
+
+	var b strings.Builder
+	if result.LibraryID > 0 {
+		fmt.Fprintf(&b, "## Documentation (libraryId: %d, version %s, page %d)\n\n", result.LibraryID, result.Version, result.Page)
+		if result.RequestedVersion != "" {
+			fmt.Fprintf(&b, "Requested version `%s` resolved to `%s`.\n\n", result.RequestedVersion, result.Version)
+		}
+		b.WriteString(renderDocumentsMarkdown(result.Documents))
+	} else {
+		// 跨库检索：文档已按库分组，每组一个二级标题
+		fmt.Fprintf(&b, "Documentation across libraries (page %d)\n", result.Page)
+		for start := 0; start < len(result.Documents); {
+			end := start + 1
+			for end < len(result.Documents) && result.Documents[end].LibraryID == result.Documents[start].LibraryID {
+				end++
+			}
+			first := result.Documents[start]
+			fmt.Fprintf(&b, "\n## %s (libraryId: %d, version %s)\n\n", first.Library, first.LibraryID, first.Version)
+			b.WriteString(renderDocumentsMarkdown(result.Documents[start:end]))
+			start = end
+		}
+	}
+	for _, expansion := range result.QueryExpansion {
+		b.WriteString(renderQueryExpansionMarkdown(expansion))
+	}
+	if result.TokenBudget > 0 {
+		fmt.Fprintf(&b, "\nTokens used: %d / %d\n", result.TokensUsed, result.TokenBudget)
+	} else {
+		fmt.Fprintf(&b, "\nTokens used: %d\n", result.TokensUsed)
+	}
+	if result.HasMore {
+		fmt.Fprintf(&b, "\nMore results available: call again with page %d.\n", result.Page+1)
+	}
+	return b.String()
+}
+
+// renderDependenciesMarkdown 将 resolve-dependencies 结果渲染为 Markdown（字段与 structuredContent 一致）
+func renderDependenciesMarkdown(result *response.MCPResolveDependenciesResult) string {
+	if len(result.Dependencies) == 0 {
+		return "No dependencies found in the manifests.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Matched %d of %d dependencies. Pass `libraryId` and `version` to get-library-docs.\n\n", result.Matched, len(result.Dependencies))
+	// 版本约束可能包含 ||，需要转义以免破坏表格
+	cell := func(s string) string { return strings.ReplaceAll(s, "|", "\\|") }
+	b.WriteString("| Dependency | Requested | Library | libraryId | Version | Match |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, dep := range result.Dependencies {
+		name := dep.Name
+		if dep.Dev {
+			name += " (dev)"
+		}
+		if dep.LibraryID == 0 {
+			fmt.Fprintf(&b, "| %s | %s | - | - | - | not indexed |\n", name, cell(dep.Requested))
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s | %s, %s version |\n",
+			name, cell(dep.Requested), dep.Library, dep.LibraryID, dep.Version, dep.MatchedBy, dep.VersionMatch)
+	}
+	return b.String()
+}
+
+// sourceLink 来源为URL时渲染为链接，否则渲染为代码片段
+func sourceLink(source string) string {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return fmt.Sprintf("[%s](%s)", source, source)
+	}
+	return "`" + source + "`"
+}
+
+// codeFence 返回比代码中最长反引号序列更长的围栏，避免代码内的 ``` 提前结束代码块
+func codeFence(code string) string {
+	longest, current := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			current++
+			if current > longest {
+				longest = current
+			}
+		} else {
+			current = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// renderQueryExpansionMarkdown 渲染单个 topic 的查询扩展情况
+func renderQueryExpansionMarkdown(expansion response.QueryExpansion) string {
+	if !expansion.Applied {
+		return fmt.Sprintf("\nQuery expansion for `%s`: not applied (searched the topic as given).\n", expansion.Topic)
+	}
+	var parts []string
+	if len(expansion.Queries) > 0 {
+		parts = append(parts, "expanded to "+strings.Join(expansion.Queries, "; "))
+	}
+	if expansion.HyDE {
+		parts = append(parts, "searched with a hypothetical answer passage")
+	}
+	return fmt.Sprintf("\nQuery expansion for `%s`: %s.\n", expansion.Topic, strings.Join(parts, ", "))
+}
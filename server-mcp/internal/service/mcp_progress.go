@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// MCP progress 能力
+//
+// 请求携带 _meta.progressToken 时，工具调用在返回最终结果之前通过响应流
+// 推送 notifications/progress（按 topic、按阶段：向量生成 → 向量检索 → 关键词检索）。
+// Streamable HTTP 此时改用SSE响应，SSE协议直接推送到会话连接。
+
+// MCPProgress 向当前请求推送 notifications/progress 的进度报告器
+// nil 表示不推送，所有方法对 nil 安全；多 topic 并行检索时并发安全
+type MCPProgress struct {
+	mu       sync.Mutex
+	token    interface{}
+	writer   transport.NotificationWriter
+	progress float64
+	total    float64
+}
+
+// NewMCPProgress 创建进度报告器（未携带 progressToken 或写入器不支持通知时返回 nil）
+func NewMCPProgress(token interface{}, writer transport.ResponseWriter) *MCPProgress {
+	if token == nil {
+		return nil
+	}
+	notificationWriter, ok := writer.(transport.NotificationWriter)
+	if !ok {
+		return nil
+	}
+	return &MCPProgress{token: token, writer: notificationWriter}
+}
+
+// AddTotal 增加总步数（检索开始时按 topic 数量确定）
+func (p *MCPProgress) AddTotal(steps int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.total += float64(steps)
+	p.mu.Unlock()
+}
+
+// Advance 完成若干步并推送进度（progress 单调递增）
+func (p *MCPProgress) Advance(steps int, message string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.progress += float64(steps)
+	params := map[string]interface{}{
+		"progressToken": p.token,
+		"progress":      p.progress,
+		"message":       message,
+	}
+	if p.total >= p.progress {
+		params["total"] = p.total
+	}
+
+	notification := response.NewMCPNotification("notifications/progress", params)
+	if err := p.writer.WriteNotification(notification); err != nil {
+		global.Log.Debug("推送MCP进度失败", zap.Error(err))
+	}
+}
+
+// mcpProgressKey context 中存放 MCPProgress 的 key
+type mcpProgressKey struct{}
+
+// WithMCPProgress 将进度报告器放入 context，供 service 各层报告进度
+func WithMCPProgress(ctx context.Context, progress *MCPProgress) context.Context {
+	if progress == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, mcpProgressKey{}, progress)
+}
+
+// mcpProgress 获取 context 中的进度报告器（没有时返回 nil）
+func mcpProgress(ctx context.Context) *MCPProgress {
+	p, _ := ctx.Value(mcpProgressKey{}).(*MCPProgress)
+	return p
+}
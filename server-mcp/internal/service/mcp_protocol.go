@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// MCP 协议版本协商
+//
+// 客户端在 initialize 中声明期望的版本：服务端支持则原样返回；
+// 比服务端最新版本还新时返回服务端最新版本（客户端需向下兼容）；
+// 比服务端支持的最旧版本还旧（或格式不合法）时返回错误。
+// 协商结果与客户端能力保存在会话属性中，后续请求按协商版本启用对应特性
+
+// 协议版本（日期格式，字符串比较即版本先后）
+const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20250618 = "2025-06-18"
+	ProtocolVersion20251125 = "2025-11-25"
+
+	// LatestProtocolVersion 服务端支持的最新协议版本
+	LatestProtocolVersion = ProtocolVersion20251125
+)
+
+// SupportedProtocolVersions 服务端支持的协议版本（从新到旧）
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20251125,
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// NegotiateProtocolVersion 协商协议版本（未声明版本的旧客户端按最新版本处理）
+func NegotiateProtocolVersion(requested string) (string, error) {
+	if requested == "" {
+		return LatestProtocolVersion, nil
+	}
+	if !isProtocolVersionFormat(requested) {
+		return "", ErrUnsupportedProtocolVersion
+	}
+	for _, version := range SupportedProtocolVersions {
+		if requested >= version {
+			// 支持的版本原样返回；更新的版本降级到服务端能提供的最高版本
+			return version, nil
+		}
+	}
+	return "", ErrUnsupportedProtocolVersion
+}
+
+// IsSupportedProtocolVersion 判断是否为服务端支持的协议版本
+func IsSupportedProtocolVersion(version string) bool {
+	for _, supported := range SupportedProtocolVersions {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// isProtocolVersionFormat 校验版本号格式（YYYY-MM-DD）
+func isProtocolVersionFormat(version string) bool {
+	if len(version) != len("2006-01-02") {
+		return false
+	}
+	for i, ch := range version {
+		if i == 4 || i == 7 {
+			if ch != '-' {
+				return false
+			}
+		} else if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// protocolVersionAtLeast 判断协议版本是否不早于 min
+func protocolVersionAtLeast(version, min string) bool {
+	return version >= min
+}
+
+// 按协议版本启用的特性
+
+// supportsCompletions completions 能力（2025-03-26 引入）
+func supportsCompletions(version string) bool {
+	return protocolVersionAtLeast(version, ProtocolVersion20250326)
+}
+
+// supportsStructuredContent 工具 outputSchema 与 structuredContent（2025-06-18 引入）
+func supportsStructuredContent(version string) bool {
+	return protocolVersionAtLeast(version, ProtocolVersion20250618)
+}
+
+// supportsElicitation elicitation/create（2025-06-18 引入）
+func supportsElicitation(version string) bool {
+	return protocolVersionAtLeast(version, ProtocolVersion20250618)
+}
+
+// clientProtocolVersion 当前请求适用的协议版本
+// 优先使用会话中协商的版本，其次是请求头声明的受支持版本，都没有时按最新版本处理
+func (h *MCPHandler) clientProtocolVersion(req *transport.RequestContext) string {
+	if client := h.resolveClient(req); client != nil && client.ProtocolVersion != "" {
+		return client.ProtocolVersion
+	}
+	if IsSupportedProtocolVersion(req.ProtocolVersion) {
+		return req.ProtocolVersion
+	}
+	return LatestProtocolVersion
+}
+
+// resolveClient 获取请求对应的客户端信息（首次调用时从会话属性加载并缓存到请求上下文）
+func (h *MCPHandler) resolveClient(req *transport.RequestContext) *transport.ClientInfo {
+	if req.Client != nil || req.SessionID == "" {
+		return req.Client
+	}
+	store := session.GetStore()
+	if store == nil {
+		return nil
+	}
+
+	data, err := store.GetAttr(req.Context(), req.SessionID, session.AttrClient)
+	if err != nil || data == "" {
+		return nil
+	}
+	var client transport.ClientInfo
+	if err := json.Unmarshal([]byte(data), &client); err != nil {
+		global.Log.Warn("解析会话客户端信息失败", zap.String("session_id", req.SessionID), zap.Error(err))
+		return nil
+	}
+	req.Client = &client
+	req.Set("mcp_client", req.Client)
+	return req.Client
+}
+
+// saveClient 保存 initialize 协商结果到会话属性（无会话时只记录在请求上下文）
+func (h *MCPHandler) saveClient(ctx context.Context, req *transport.RequestContext, client *transport.ClientInfo) {
+	req.Client = client
+	req.Set("mcp_client", client)
+
+	store := session.GetStore()
+	if req.SessionID == "" || store == nil {
+		return
+	}
+	data, err := json.Marshal(client)
+	if err != nil {
+		return
+	}
+	if err := store.SetAttr(ctx, req.SessionID, session.AttrClient, string(data)); err != nil {
+		global.Log.Warn("保存会话客户端信息失败", zap.String("session_id", req.SessionID), zap.Error(err))
+	}
+}
+
+// parseClientInfo 解析 initialize 参数中的客户端能力与身份
+func parseClientInfo(params map[string]interface{}, protocolVersion string) *transport.ClientInfo {
+	client := &transport.ClientInfo{ProtocolVersion: protocolVersion}
+	if capabilities, ok := params["capabilities"].(map[string]interface{}); ok {
+		client.Capabilities = capabilities
+	}
+	if info, ok := params["clientInfo"].(map[string]interface{}); ok {
+		client.Name, _ = info["name"].(string)
+		client.Version, _ = info["version"].(string)
+	}
+	return client
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go-mcp-context/internal/event"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// MCP 资源订阅与变更通知
+//
+// 订阅关系保存在会话存储（Redis）中，任意实例发布的文档变更事件都能找到订阅者，
+// 会话终止（DELETE /mcp）或 SSE 连接断开时清理该会话的订阅。
+//
+// 文档变更时向匹配且仍存活的会话推送 notifications/resources/updated，
+// 已失效会话（会话过期、SSE 连接已断开）的订阅在通知时移除。
+// 库、工具或提示集合变化时向所有会话广播 list_changed 通知。
+
+// resourceRef 资源URI解析结果
+type resourceRef struct {
+	LibraryID uint
+	Version   string // library 资源为空
+}
+
+// parseResourceURI 解析可订阅的资源URI
+// 支持 go-mcp-context:///library/{libraryId} 和 go-mcp-context:///docs/chunk/{libraryId}/{version}/{topic}
+func parseResourceURI(uri string) (*resourceRef, error) {
+	parsedURL, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uri format: %s", uri)
+	}
+
+	var segments []string
+	for _, seg := range strings.Split(strings.TrimPrefix(parsedURL.Path, "/"), "/") {
+		if seg != "" {
+			segments = append(segments, seg)
+		}
+	}
+
+	var ref resourceRef
+	var idSegment string
+	switch {
+	case len(segments) == 2 && segments[0] == "library":
+		idSegment = segments[1]
+	case len(segments) == 5 && segments[0] == "docs" && segments[1] == "chunk":
+		idSegment = segments[2]
+		ref.Version = segments[3]
+	default:
+		return nil, fmt.Errorf("unsupported resource uri: %s", uri)
+	}
+
+	if _, err := fmt.Sscanf(idSegment, "%d", &ref.LibraryID); err != nil || ref.LibraryID == 0 {
+		return nil, fmt.Errorf("invalid libraryId in uri: %s", uri)
+	}
+	return &ref, nil
+}
+
+// subscribeResource 记录会话对资源的订阅
+func subscribeResource(ctx context.Context, store *session.Store, sessionID, uri string, ref *resourceRef) error {
+	return store.AddResourceSubscription(ctx, sessionID, ref.LibraryID, uri)
+}
+
+// unsubscribeResource 取消会话对资源的订阅
+func unsubscribeResource(ctx context.Context, store *session.Store, sessionID, uri string, ref *resourceRef) error {
+	return store.RemoveResourceSubscription(ctx, sessionID, ref.LibraryID, uri)
+}
+
+// notifyResourceUpdated 文档变更事件处理：通知订阅了相关资源的会话
+func notifyResourceUpdated(e event.Event) {
+	store := session.GetStore()
+	if store == nil {
+		return
+	}
+
+	ctx := context.Background()
+	subs, err := store.ResourceSubscriptions(ctx, e.LibraryID)
+	if err != nil {
+		global.Log.Warn("读取资源订阅失败", zap.Uint("library_id", e.LibraryID), zap.Error(err))
+		return
+	}
+
+	for _, sub := range subs {
+		// 版本变更只影响该版本的文档资源，库资源总是通知
+		ref, err := parseResourceURI(sub.URI)
+		if err != nil {
+			continue
+		}
+		if e.Version != "" && ref.Version != "" && ref.Version != e.Version {
+			continue
+		}
+
+		// Redis 发布没有接收者也会成功，需先确认会话仍存活
+		alive, err := store.Alive(ctx, sub.SessionID)
+		if err != nil {
+			global.Log.Warn("检查会话存活失败", zap.String("session_id", sub.SessionID), zap.Error(err))
+			continue
+		}
+		if !alive {
+			if err := store.ClearResourceSubscriptions(ctx, sub.SessionID); err != nil {
+				global.Log.Warn("清理失效会话的资源订阅失败", zap.String("session_id", sub.SessionID), zap.Error(err))
+			}
+			continue
+		}
+
+		notification := response.NewMCPNotification("notifications/resources/updated", map[string]interface{}{
+			"uri": sub.URI,
+		})
+		if err := transport.Push(ctx, sub.SessionID, notification); err != nil {
+			global.Log.Warn("推送资源变更通知失败", zap.String("session_id", sub.SessionID), zap.Error(err))
+		}
+	}
+}
+
+// notifyResourcesListChanged 库集合变化：通知所有会话重新获取 resources/list
+func notifyResourcesListChanged(e event.Event) {
+	broadcastNotification("notifications/resources/list_changed")
+}
+
+// notifyToolsListChanged 工具集合变化：通知所有会话重新获取 tools/list
+func notifyToolsListChanged(e event.Event) {
+	broadcastNotification("notifications/tools/list_changed")
+}
+
+// notifyPromptsListChanged 提示模板变化：通知所有会话重新获取 prompts/list
+func notifyPromptsListChanged(e event.Event) {
+	broadcastNotification("notifications/prompts/list_changed")
+}
+
+// broadcastNotification 向所有会话推送无参数的通知
+func broadcastNotification(method string) {
+	if err := transport.Broadcast(context.Background(), response.NewMCPNotification(method, nil)); err != nil {
+		global.Log.Warn("广播MCP通知失败", zap.String("method", method), zap.Error(err))
+	}
+}
+
+// RegisterMCPEventHandlers 注册 MCP 对内部事件的订阅
+func RegisterMCPEventHandlers() {
+	event.Subscribe(event.TopicDocsChanged, notifyResourceUpdated)
+	event.Subscribe(event.TopicLibrariesChanged, notifyResourcesListChanged)
+	event.Subscribe(event.TopicToolsChanged, notifyToolsListChanged)
+	event.Subscribe(event.TopicPromptsChanged, notifyPromptsListChanged)
+}
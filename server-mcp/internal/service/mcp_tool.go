@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go-mcp-context/internal/event"
+	"go-mcp-context/pkg/global"
+)
+
+// MCP 工具注册表
+//
+// tools/list、tools/call 与调用日志中间件都从注册表读取工具定义，
+// 新增工具只需实现 MCPTool 并在 init 中调用 MCPTools.Register；
+// 配置 mcp.disabled_tools 可以按名称关闭工具（关闭后不出现在 tools/list 中，调用返回未知工具）
+
+// MCPTool MCP 工具
+type MCPTool interface {
+	// Name 工具名（tools/call 的 name）
+	Name() string
+
+	// Description 工具描述
+	Description() string
+
+	// InputSchema 参数 JSON Schema，调用前按此校验参数
+	InputSchema() map[string]interface{}
+
+	// OutputSchema 输出 JSON Schema（与 structuredContent 对应，无结构化输出时返回 nil）
+	OutputSchema() map[string]interface{}
+
+	// Call 执行工具（参数已通过 InputSchema 校验）
+	// 参数不合法返回 *ToolParamsError（JSON-RPC -32602），其他错误以 isError 结果返回给模型
+	Call(ctx context.Context, args map[string]interface{}) (*MCPToolResult, error)
+}
+
+// MCPToolResult 工具执行结果
+type MCPToolResult struct {
+	Text        string      // 返回给模型的文本（通常为结构化结果的 Markdown 渲染）
+	Structured  interface{} // structuredContent（可为 nil）
+	ResultCount int         // 结果数量（调用日志）
+	LibraryID   uint        // 关联库ID（调用日志，0 表示无）
+}
+
+// MCPToolRegistry 工具注册表（并发安全，按注册顺序列出）
+type MCPToolRegistry struct {
+	mu    sync.RWMutex
+	tools []MCPTool
+}
+
+// MCPTools 全局工具注册表
+var MCPTools = NewMCPToolRegistry()
+
+// NewMCPToolRegistry 创建工具注册表
+func NewMCPToolRegistry() *MCPToolRegistry {
+	return &MCPToolRegistry{}
+}
+
+// Register 注册工具（同名工具替换原定义），并通知客户端工具列表变化
+func (r *MCPToolRegistry) Register(tool MCPTool) {
+	r.add(tool)
+	event.Publish(event.Event{Topic: event.TopicToolsChanged})
+}
+
+// Unregister 注销工具
+func (r *MCPToolRegistry) Unregister(name string) {
+	r.mu.Lock()
+	n := len(r.tools)
+	r.tools = slices.DeleteFunc(r.tools, func(tool MCPTool) bool { return tool.Name() == name })
+	removed := len(r.tools) != n
+	r.mu.Unlock()
+
+	if removed {
+		event.Publish(event.Event{Topic: event.TopicToolsChanged})
+	}
+}
+
+// add 注册工具（不发布变化事件，内置工具初始化时使用）
+func (r *MCPToolRegistry) add(tool MCPTool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.tools {
+		if existing.Name() == tool.Name() {
+			r.tools[i] = tool
+			return
+		}
+	}
+	r.tools = append(r.tools, tool)
+}
+
+// Get 获取已启用的工具
+func (r *MCPToolRegistry) Get(name string) (MCPTool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, tool := range r.tools {
+		if tool.Name() == name {
+			return tool, isToolEnabled(name)
+		}
+	}
+	return nil, false
+}
+
+// List 列出已启用的工具
+func (r *MCPToolRegistry) List() []MCPTool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]MCPTool, 0, len(r.tools))
+	for _, tool := range r.tools {
+		if isToolEnabled(tool.Name()) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// Has 工具是否已注册（包括已关闭的工具，调用日志按此记录工具名）
+func (r *MCPToolRegistry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, tool := range r.tools {
+		if tool.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isToolEnabled 工具是否启用（未在 mcp.disabled_tools 中）
+func isToolEnabled(name string) bool {
+	if global.Config == nil {
+		return true
+	}
+	return !slices.Contains(global.Config.MCP.DisabledTools, name)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+
+	"go-mcp-context/internal/model/request"
+)
+
+// 依赖解析工具：resolve-dependencies
+
+// 编译时检查接口实现
+var _ MCPTool = (*resolveDependenciesTool)(nil)
+
+func init() {
+	MCPTools.add(&resolveDependenciesTool{mcpService: NewMCPService()})
+}
+
+// resolveDependenciesTool resolve-dependencies 工具
+type resolveDependenciesTool struct {
+	mcpService *MCPService
+}
+
+func (t *resolveDependenciesTool) Name() string {
+	return "resolve-dependencies"
+}
+
+func (t *resolveDependenciesTool) Description() string {
+	return "Resolve a project's dependencies to documentation libraries. Pass the contents of go.mod, package.json, requirements.txt or pom.xml; returns, for each dependency, the matching libraryId and the closest indexed version to use with get-library-docs. Dependencies without a matching library are returned without libraryId."
+}
+
+func (t *resolveDependenciesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"manifests": map[string]interface{}{
+				"type":        "array",
+				"description": "Dependency manifest files",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"filename": map[string]interface{}{
+							"type":        "string",
+							"description": "Manifest file name: go.mod, package.json, requirements.txt (or requirements-*.txt) or pom.xml",
+							"minLength":   1,
+						},
+						"content": map[string]interface{}{
+							"type":        "string",
+							"description": "Manifest file content",
+							"maxLength":   maxManifestLength,
+						},
+					},
+					"required": []string{"filename", "content"},
+				},
+			},
+		},
+		"required": []string{"manifests"},
+	}
+}
+
+func (t *resolveDependenciesTool) OutputSchema() map[string]interface{} {
+	return resolveDependenciesOutputSchema
+}
+
+func (t *resolveDependenciesTool) Call(ctx context.Context, args map[string]interface{}) (*MCPToolResult, error) {
+	var manifests []request.MCPManifest
+	items, _ := args["manifests"].([]interface{})
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		filename, _ := m["filename"].(string)
+		content, _ := m["content"].(string)
+		manifests = append(manifests, request.MCPManifest{Filename: filename, Content: content})
+	}
+	if len(manifests) == 0 {
+		return nil, &ToolParamsError{Message: "manifests is required"}
+	}
+
+	result, err := t.mcpService.ResolveDependenciesWithContext(ctx, &request.MCPResolveDependencies{Manifests: manifests})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MCPToolResult{
+		Text:        renderDependenciesMarkdown(result),
+		Structured:  result,
+		ResultCount: result.Matched,
+	}, nil
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+)
+
+// 内置文档检索工具：search-libraries、get-library-docs
+
+// 编译时检查接口实现
+var (
+	_ MCPTool = (*searchLibrariesTool)(nil)
+	_ MCPTool = (*getLibraryDocsTool)(nil)
+)
+
+func init() {
+	mcpService := NewMCPService()
+	MCPTools.add(&searchLibrariesTool{mcpService: mcpService})
+	MCPTools.add(&getLibraryDocsTool{mcpService: mcpService})
+}
+
+// searchLibrariesTool search-libraries 工具
+type searchLibrariesTool struct {
+	mcpService *MCPService
+}
+
+func (t *searchLibrariesTool) Name() string {
+	return "search-libraries"
+}
+
+func (t *searchLibrariesTool) Description() string {
+	return "Search for documentation libraries by name using semantic vector search (primary) with fuzzy matching fallback. Returns matching libraries with metadata including available versions. Use this method to discover libraries and get their version information (versions array and defaultVersion) before calling get-library-docs."
+}
+
+func (t *searchLibrariesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"libraryName": map[string]interface{}{
+				"type":        "string",
+				"description": "The name of the library to search for",
+			},
+		},
+		"required": []string{"libraryName"},
+	}
+}
+
+func (t *searchLibrariesTool) OutputSchema() map[string]interface{} {
+	return searchLibrariesOutputSchema
+}
+
+func (t *searchLibrariesTool) Call(ctx context.Context, args map[string]interface{}) (*MCPToolResult, error) {
+	libraryName, _ := args["libraryName"].(string)
+	if libraryName == "" {
+		return nil, &ToolParamsError{Message: "libraryName is required"}
+	}
+
+	result, err := t.mcpService.SearchLibrariesWithContext(ctx, &request.MCPSearchLibraries{LibraryName: libraryName})
+	if err != nil {
+		return nil, err
+	}
+
+	// 多个相近的候选库：客户端支持 elicitation 时请用户选择，只返回选中的库
+	if chosen, ok := elicitLibrary(ctx, libraryName, result.Libraries); ok {
+		result.Libraries = []response.MCPLibraryInfo{chosen}
+	}
+
+	return &MCPToolResult{
+		Text:        renderLibrariesMarkdown(result),
+		Structured:  result,
+		ResultCount: len(result.Libraries),
+	}, nil
+}
+
+// minTokenBudget get-library-docs 每页 token 预算下限
+const minTokenBudget = 100
+
+// getLibraryDocsTool get-library-docs 工具
+type getLibraryDocsTool struct {
+	mcpService *MCPService
+}
+
+func (t *getLibraryDocsTool) Name() string {
+	return "get-library-docs"
+}
+
+func (t *getLibraryDocsTool) Description() string {
+	return fmt.Sprintf("Get documentation for a specific library (libraryId), or search across libraries by omitting libraryId: the libraries most relevant to the topic (up to %d), or the subset given in libraryIds. Version defaults to each library's default version and accepts aliases ('latest', 'stable') and semver ranges; the resolved version is returned. Cross-library results are grouped and labeled by library. Pass tokens to pack each page up to a token budget; the tokens used are returned. Supports comma-separated topics for multi-topic search.", MaxCrossLibraries)
+}
+
+func (t *getLibraryDocsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"libraryId": map[string]interface{}{
+				"type":        "integer",
+				"description": "Library ID from search-libraries (omit to search across libraries)",
+			},
+			"libraryIds": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "integer", "minimum": 1},
+				"maxItems":    MaxCrossLibraries,
+				"description": "Library IDs to search across when libraryId is omitted (default: the libraries most relevant to the topic)",
+			},
+			"topic": map[string]interface{}{
+				"type":        "string",
+				"description": "Documentation topic (required, supports comma-separated topics like 'overview,api,examples')",
+			},
+			"version": map[string]interface{}{
+				"type":        "string",
+				"description": "Library version: exact version, 'latest', 'stable', or a semver range like '^1.9' or '1.x' (default: the library's defaultVersion; for cross-library search, libraries without a matching version use their default)",
+			},
+			"mode": map[string]interface{}{
+				"type":        "string",
+				"description": "Search mode: info or code",
+				"enum":        []string{"info", "code"},
+			},
+			"page": map[string]interface{}{
+				"type":        "integer",
+				"description": "Page number (1-10)",
+				"minimum":     1,
+				"maximum":     10,
+			},
+			"tokens": map[string]interface{}{
+				"type":        "integer",
+				"description": "Token budget per page: the most relevant snippets are packed until the budget is reached, oversized snippets are truncated (default: 10 snippets per page)",
+				"minimum":     minTokenBudget,
+			},
+		},
+		"required": []string{"topic"},
+	}
+}
+
+func (t *getLibraryDocsTool) OutputSchema() map[string]interface{} {
+	return getLibraryDocsOutputSchema
+}
+
+func (t *getLibraryDocsTool) Call(ctx context.Context, args map[string]interface{}) (*MCPToolResult, error) {
+	var libraryID uint
+	if id, ok := args["libraryId"].(float64); ok {
+		libraryID = uint(id)
+	}
+	var libraryIDs []uint
+	if ids, ok := args["libraryIds"].([]interface{}); ok {
+		for _, id := range ids {
+			if f, ok := id.(float64); ok {
+				libraryIDs = append(libraryIDs, uint(f))
+			}
+		}
+	}
+	version, _ := args["version"].(string)
+	topic, _ := args["topic"].(string)
+	mode, _ := args["mode"].(string)
+	page := 1
+	if p, ok := args["page"].(float64); ok {
+		page = int(p)
+	}
+	tokens := 0
+	if t, ok := args["tokens"].(float64); ok {
+		tokens = int(t)
+	}
+
+	if topic == "" {
+		return nil, &ToolParamsError{Message: "topic is required"}
+	}
+	if libraryID > 0 && len(libraryIDs) > 0 {
+		return nil, &ToolParamsError{Message: "libraryId and libraryIds cannot be used together"}
+	}
+
+	req := &request.MCPGetLibraryDocs{
+		LibraryID:  libraryID,
+		LibraryIDs: libraryIDs,
+		Topic:      topic,
+		Version:    version,
+		Mode:       mode,
+		Page:       page,
+		Tokens:     tokens,
+	}
+	result, err := t.mcpService.GetLibraryDocsWithContext(ctx, req)
+
+	// 请求的版本不存在：客户端支持 elicitation 时请用户从可用版本中选择，并用选择的版本继续
+	var versionErr *VersionNotFoundError
+	if errors.As(err, &versionErr) {
+		if chosen, ok := elicitVersion(ctx, libraryID, versionErr); ok {
+			req.Version = chosen
+			if result, err = t.mcpService.GetLibraryDocsWithContext(ctx, req); err == nil {
+				result.RequestedVersion = version
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, ErrNotFound) && libraryID > 0 {
+			err = fmt.Errorf("%w: libraryId %d", err, libraryID)
+		}
+		return nil, err
+	}
+
+	return &MCPToolResult{
+		Text:        renderLibraryDocsMarkdown(result),
+		Structured:  result,
+		ResultCount: len(result.Documents),
+		LibraryID:   result.LibraryID,
+	}, nil
+}
+
+// 候选库歧义判断：名称匹配分数与最高分相差不超过 ambiguousScoreMargin 的库视为相近候选
+const (
+	ambiguousScoreMargin   = 0.1
+	maxElicitedLibraries   = 5
+	maxElicitedVersions    = 20
+	elicitDescriptionLimit = 80
+)
+
+// elicitLibrary 多个相近的候选库（且没有名称完全一致的库）时请用户选择
+func elicitLibrary(ctx context.Context, libraryName string, libraries []response.MCPLibraryInfo) (response.MCPLibraryInfo, bool) {
+	elicitor := mcpElicitor(ctx)
+	if elicitor == nil || len(libraries) < 2 {
+		return response.MCPLibraryInfo{}, false
+	}
+
+	best := 0.0
+	for _, lib := range libraries {
+		best = max(best, lib.Score)
+	}
+	if best >= 1.0 {
+		return response.MCPLibraryInfo{}, false
+	}
+	var candidates []response.MCPLibraryInfo
+	for _, lib := range libraries {
+		if lib.Score >= best-ambiguousScoreMargin && len(candidates) < maxElicitedLibraries {
+			candidates = append(candidates, lib)
+		}
+	}
+	if len(candidates) < 2 {
+		return response.MCPLibraryInfo{}, false
+	}
+
+	options := make([]string, len(candidates))
+	labels := make([]string, len(candidates))
+	for i, lib := range candidates {
+		options[i] = strconv.FormatUint(uint64(lib.LibraryID), 10)
+		labels[i] = lib.Name
+		if description := truncateRunes(strings.TrimSpace(lib.Description), elicitDescriptionLimit); description != "" {
+			labels[i] += " - " + description
+		}
+	}
+	choice, ok, err := elicitor.ElicitChoice(ctx,
+		fmt.Sprintf("Several libraries match %q. Which one do you mean?", libraryName),
+		"libraryId", "Library", options, labels)
+	if err != nil {
+		mcpLog(ctx, "warning", "search-libraries", map[string]interface{}{"elicitation": "failed", "error": err.Error()})
+	}
+	if !ok {
+		return response.MCPLibraryInfo{}, false
+	}
+	for _, lib := range candidates {
+		if strconv.FormatUint(uint64(lib.LibraryID), 10) == choice {
+			return lib, true
+		}
+	}
+	return response.MCPLibraryInfo{}, false
+}
+
+// elicitVersion 请求的版本不存在时请用户从可用版本中选择
+func elicitVersion(ctx context.Context, libraryID uint, versionErr *VersionNotFoundError) (string, bool) {
+	elicitor := mcpElicitor(ctx)
+	if elicitor == nil || libraryID == 0 || len(versionErr.Available) == 0 {
+		return "", false
+	}
+
+	options := versionErr.Available
+	if len(options) > maxElicitedVersions {
+		options = options[:maxElicitedVersions]
+	}
+	choice, ok, err := elicitor.ElicitChoice(ctx,
+		fmt.Sprintf("Version %q is not indexed for library %d. Which version should be used?", versionErr.Version, libraryID),
+		"version", "Version", options, nil)
+	if err != nil {
+		mcpLog(ctx, "warning", "get-library-docs", map[string]interface{}{"elicitation": "failed", "error": err.Error()})
+	}
+	return choice, ok
+}
+
+// truncateRunes 按字符数截断文本
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MCP 工具输出结构（outputSchema），与 structuredContent 对应的响应结构体保持一致
+
+// searchLibrariesOutputSchema search-libraries 输出结构（response.MCPSearchLibrariesResult）
+var searchLibrariesOutputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"libraries": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"libraryId":      map[string]interface{}{"type": "integer", "description": "Library ID for get-library-docs"},
+					"name":           map[string]interface{}{"type": "string"},
+					"versions":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					"defaultVersion": map[string]interface{}{"type": "string"},
+					"description":    map[string]interface{}{"type": "string"},
+					"snippets":       map[string]interface{}{"type": "integer", "description": "Number of documentation snippets"},
+					"score":          map[string]interface{}{"type": "number", "description": "Name match score"},
+				},
+				"required": []string{"libraryId", "name", "versions", "defaultVersion", "description", "snippets", "score"},
+			},
+		},
+	},
+	"required": []string{"libraries"},
+}
+
+// getLibraryDocsOutputSchema get-library-docs 输出结构（response.MCPGetLibraryDocsResult）
+var getLibraryDocsOutputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"libraryId":        map[string]interface{}{"type": "integer", "description": "Library ID (0 for cross-library search)"},
+		"version":          map[string]interface{}{"type": "string", "description": "Resolved version that was searched (single-library search)"},
+		"requestedVersion": map[string]interface{}{"type": "string", "description": "Requested version, when it differs from the resolved version"},
+		"documents": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"libraryId":   map[string]interface{}{"type": "integer"},
+					"library":     map[string]interface{}{"type": "string", "description": "Library name"},
+					"title":       map[string]interface{}{"type": "string"},
+					"description": map[string]interface{}{"type": "string"},
+					"source":      map[string]interface{}{"type": "string", "description": "Source file path or URL"},
+					"version":     map[string]interface{}{"type": "string"},
+					"mode":        map[string]interface{}{"type": "string", "enum": []string{"code", "info"}},
+					"language":    map[string]interface{}{"type": "string"},
+					"code":        map[string]interface{}{"type": "string"},
+					"content":     map[string]interface{}{"type": "string"},
+					"tokens":      map[string]interface{}{"type": "integer"},
+					"truncated":   map[string]interface{}{"type": "boolean", "description": "Whether the snippet was truncated to fit the token budget"},
+					"relevance":   map[string]interface{}{"type": "number", "description": "Relevance score 0-1"},
+				},
+				"required": []string{"libraryId", "library", "title", "source", "version", "mode", "tokens", "relevance"},
+			},
+		},
+		"page":        map[string]interface{}{"type": "integer"},
+		"hasMore":     map[string]interface{}{"type": "boolean"},
+		"tokenBudget": map[string]interface{}{"type": "integer", "description": "Requested token budget per page"},
+		"tokensUsed":  map[string]interface{}{"type": "integer", "description": "Total tokens of the returned documents"},
+		"queryExpansion": map[string]interface{}{
+			"type":        "array",
+			"description": "Per-topic LLM query expansion (present when query expansion is enabled)",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"topic":   map[string]interface{}{"type": "string"},
+					"applied": map[string]interface{}{"type": "boolean", "description": "Whether expansion was applied (false when the topic was searched as given)"},
+					"queries": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Expanded sub-queries"},
+					"hyde":    map[string]interface{}{"type": "boolean", "description": "Whether a hypothetical answer passage was used for vector search"},
+				},
+				"required": []string{"topic", "applied"},
+			},
+		},
+	},
+	"required": []string{"libraryId", "documents", "page", "hasMore", "tokensUsed"},
+}
+
+// resolveDependenciesOutputSchema resolve-dependencies 输出结构（response.MCPResolveDependenciesResult）
+var resolveDependenciesOutputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"dependencies": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":         map[string]interface{}{"type": "string", "description": "Dependency name as declared in the manifest"},
+					"ecosystem":    map[string]interface{}{"type": "string", "enum": []string{"go", "npm", "pypi", "maven"}},
+					"manifest":     map[string]interface{}{"type": "string", "description": "Manifest file name"},
+					"requested":    map[string]interface{}{"type": "string", "description": "Version or version constraint declared in the manifest"},
+					"dev":          map[string]interface{}{"type": "boolean", "description": "Development or test-only dependency"},
+					"libraryId":    map[string]interface{}{"type": "integer", "description": "Matching library ID for get-library-docs (absent when no library matches)"},
+					"library":      map[string]interface{}{"type": "string", "description": "Matching library name"},
+					"version":      map[string]interface{}{"type": "string", "description": "Closest indexed version for get-library-docs"},
+					"versionMatch": map[string]interface{}{"type": "string", "enum": []string{"exact", "range", "closest", "default"}},
+					"matchedBy":    map[string]interface{}{"type": "string", "enum": []string{"source_url", "alias", "name"}},
+					"score":        map[string]interface{}{"type": "number", "description": "Library match score 0-1"},
+				},
+				"required": []string{"name", "ecosystem", "manifest"},
+			},
+		},
+		"matched": map[string]interface{}{"type": "integer", "description": "Number of dependencies matched to a library"},
+	},
+	"required": []string{"dependencies", "matched"},
+}
+
+// validateToolArguments 按工具 inputSchema 校验调用参数
+// 支持工具定义中常用的 JSON Schema 子集：type、properties、required、enum、minimum/maximum、
+// minLength/maxLength、items、additionalProperties(false)
+func validateToolArguments(schema map[string]interface{}, args map[string]interface{}) error {
+	return validateSchemaValue(schema, args, "")
+}
+
+// validateSchemaValue 递归校验单个值（path 为参数路径，用于错误信息）
+func validateSchemaValue(schema map[string]interface{}, value interface{}, path string) error {
+	if schemaType, ok := schema["type"]; ok && !matchesSchemaType(schemaType, value) {
+		return fmt.Errorf("%s must be of type %v", schemaPathName(path), schemaType)
+	}
+
+	if enum := schemaStrings(schema["enum"]); len(enum) > 0 {
+		if str, ok := value.(string); !ok || !slices.Contains(enum, str) {
+			return fmt.Errorf("%s must be one of: %s", schemaPathName(path), strings.Join(enum, ", "))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s is required", joinSchemaPath(path, name))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, propValue := range v {
+			propSchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("unexpected argument %s", joinSchemaPath(path, name))
+				}
+				continue
+			}
+			if err := validateSchemaValue(propSchema, propValue, joinSchemaPath(path, name)); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchemaValue(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case float64:
+		if minimum, ok := schemaNumber(schema["minimum"]); ok && v < minimum {
+			return fmt.Errorf("%s must be >= %v", schemaPathName(path), minimum)
+		}
+		if maximum, ok := schemaNumber(schema["maximum"]); ok && v > maximum {
+			return fmt.Errorf("%s must be <= %v", schemaPathName(path), maximum)
+		}
+
+	case string:
+		length := utf8.RuneCountInString(v)
+		if minLength, ok := schemaNumber(schema["minLength"]); ok && float64(length) < minLength {
+			return fmt.Errorf("%s must be at least %v characters", schemaPathName(path), minLength)
+		}
+		if maxLength, ok := schemaNumber(schema["maxLength"]); ok && float64(length) > maxLength {
+			return fmt.Errorf("%s must be at most %v characters", schemaPathName(path), maxLength)
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType 判断值是否符合 type（字符串或类型数组）
+func matchesSchemaType(schemaType interface{}, value interface{}) bool {
+	types := schemaStrings(schemaType)
+	if types == nil {
+		if t, ok := schemaType.(string); ok {
+			types = []string{t}
+		}
+	}
+	for _, t := range types {
+		switch v := value.(type) {
+		case string:
+			if t == "string" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case nil:
+			if t == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaStrings 读取字符串数组（Go 定义的 []string 或 JSON 解析的 []interface{}）
+func schemaStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// schemaNumber 读取数值约束
+func schemaNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func schemaPathName(path string) string {
+	if path == "" {
+		return "arguments"
+	}
+	return path
+}
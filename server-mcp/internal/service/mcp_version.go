@@ -0,0 +1,104 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	dbmodel "go-mcp-context/internal/model/database"
+	"go-mcp-context/pkg/utils"
+)
+
+// 库版本解析
+//
+// get-library-docs 的 version 参数不要求与库中的版本字符串完全一致，按以下顺序解析为实际存在的版本：
+//  1. 为空：库的默认版本
+//  2. 与某个版本完全一致（包括名为 latest 的版本）
+//  3. 忽略 v 前缀后一致（1.9.0 与 v1.9.0）
+//  4. 别名 latest / stable：语义化版本中最高的版本 / 最高的正式版本（没有时使用默认版本）
+//  5. 语义化版本范围（^1.9、~1.9.2、1.x、>=1.2 <2 等）：满足范围的最高版本
+
+// 版本别名
+const (
+	VersionAliasLatest = "latest"
+	VersionAliasStable = "stable"
+)
+
+// resolveLibraryVersion 将请求的版本解析为库中实际存在的版本，无法解析时返回 *VersionNotFoundError
+func resolveLibraryVersion(library *dbmodel.Library, requested string) (string, error) {
+	requested = strings.TrimSpace(requested)
+	defaultVersion := library.DefaultVersion
+	if defaultVersion == "" {
+		defaultVersion = VersionAliasLatest
+	}
+	if requested == "" {
+		return defaultVersion, nil
+	}
+
+	available := libraryVersions(library)
+	if slices.Contains(available, requested) {
+		return requested, nil
+	}
+
+	// v 前缀差异
+	normalized := trimVersionPrefix(requested)
+	for _, version := range available {
+		if strings.EqualFold(trimVersionPrefix(version), normalized) {
+			return version, nil
+		}
+	}
+
+	// 别名
+	switch strings.ToLower(requested) {
+	case VersionAliasLatest:
+		if version, ok := highestSemverVersion(available, func(utils.Semver) bool { return true }); ok {
+			return version, nil
+		}
+		return defaultVersion, nil
+	case VersionAliasStable:
+		if version, ok := highestSemverVersion(available, func(v utils.Semver) bool { return v.Prerelease == "" }); ok {
+			return version, nil
+		}
+		return defaultVersion, nil
+	}
+
+	// 语义化版本范围（表达式不合法时同样按未找到处理，返回可用版本供调用方修正）
+	if version, ok := highestSemverVersion(available, func(v utils.Semver) bool {
+		matched, _ := utils.MatchSemverRange(requested, v)
+		return matched
+	}); ok {
+		return version, nil
+	}
+
+	return "", &VersionNotFoundError{Version: requested, Available: available}
+}
+
+// highestSemverVersion 满足条件的最高版本（正式版本优先，均为预发布版本时取最高的预发布版本）
+func highestSemverVersion(versions []string, match func(utils.Semver) bool) (string, bool) {
+	var best, bestPre string
+	var bestV, bestPreV utils.Semver
+	for _, version := range versions {
+		v, ok := utils.ParseSemver(version)
+		if !ok || !match(v) {
+			continue
+		}
+		if v.Prerelease == "" {
+			if best == "" || utils.CompareSemver(v, bestV) > 0 {
+				best, bestV = version, v
+			}
+		} else if bestPre == "" || utils.CompareSemver(v, bestPreV) > 0 {
+			bestPre, bestPreV = version, v
+		}
+	}
+	if best != "" {
+		return best, true
+	}
+	return bestPre, bestPre != ""
+}
+
+// trimVersionPrefix 去掉版本号的 v/V 前缀
+func trimVersionPrefix(version string) string {
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && version[1] >= '0' && version[1] <= '9' {
+		return version[1:]
+	}
+	return version
+}
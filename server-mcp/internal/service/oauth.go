@@ -0,0 +1,76 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/pkg/global"
+)
+
+// OAuthService MCP 端点的 OAuth 受保护资源配置（MCP 授权规范）
+// access token 由 SSO 颁发，令牌校验在 middleware 中完成，这里只负责元数据和 audience 规则
+type OAuthService struct{}
+
+// ProtectedResourceMetadataPath 受保护资源元数据的 well-known 路径前缀
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// Enabled 是否启用 OAuth（配置了 mcp.oauth.resource）
+func (s *OAuthService) Enabled() bool {
+	return s.resource() != ""
+}
+
+// ProtectedResourceMetadata 构建受保护资源元数据
+func (s *OAuthService) ProtectedResourceMetadata() *response.OAuthProtectedResourceMetadata {
+	cfg := global.Config.MCP.OAuth
+	servers := cfg.AuthorizationServers
+	if len(servers) == 0 && global.Config.SSO.ServiceURL != "" {
+		servers = []string{global.Config.SSO.ServiceURL}
+	}
+	return &response.OAuthProtectedResourceMetadata{
+		Resource:               s.resource(),
+		AuthorizationServers:   servers,
+		BearerMethodsSupported: []string{"header"},
+		ScopesSupported:        cfg.ScopesSupported,
+		ResourceName:           "go-mcp-context",
+	}
+}
+
+// MetadataPaths 元数据的访问路径：按 RFC 9728 在 well-known 路径后拼接资源路径，另外保留根路径供只探测根路径的客户端使用
+func (s *OAuthService) MetadataPaths() []string {
+	paths := []string{ProtectedResourceMetadataPath}
+	if u, err := url.Parse(s.resource()); err == nil {
+		if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+			paths = append(paths, ProtectedResourceMetadataPath+p)
+		}
+	}
+	return paths
+}
+
+// ResourceMetadataURL 元数据的完整 URL（用于 WWW-Authenticate 的 resource_metadata 参数）
+func (s *OAuthService) ResourceMetadataURL() string {
+	u, err := url.Parse(s.resource())
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	paths := s.MetadataPaths()
+	return u.Scheme + "://" + u.Host + paths[len(paths)-1]
+}
+
+// AudienceAllowed access token 的 audience 是否包含本资源（忽略末尾斜杠）
+func (s *OAuthService) AudienceAllowed(audience []string) bool {
+	resource := strings.TrimSuffix(s.resource(), "/")
+	if resource == "" {
+		return false
+	}
+	for _, aud := range audience {
+		if strings.TrimSuffix(aud, "/") == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *OAuthService) resource() string {
+	return strings.TrimSpace(global.Config.MCP.OAuth.Resource)
+}
@@ -15,7 +15,6 @@ import (
 	"go-mcp-context/pkg/llm"
 
 	"github.com/pgvector/pgvector-go"
-	"github.com/pkoukk/tiktoken-go"
 )
 
 // 分块配置常量
@@ -186,7 +185,7 @@ func (p *DocumentProcessor) chunkText(text string, uploadID, libraryID uint, ver
 			continue
 		}
 
-		sectionTokens := p.countTokens(content)
+		sectionTokens := countTextTokens(content)
 
 		// 如果 section 小于 chunkSize，直接作为一个 chunk
 		if sectionTokens <= chunkSize {
@@ -379,7 +378,7 @@ func (p *DocumentProcessor) splitLargeSectionWithMetadata(text string, chunkSize
 			continue
 		}
 
-		atomTokens := p.countTokens(atom)
+		atomTokens := countTextTokens(atom)
 
 		// 如果单个原子块就超过 chunkSize，单独作为一个 chunk（不再切分）
 		if atomTokens > chunkSize {
@@ -517,18 +516,6 @@ func (p *DocumentProcessor) detectChunkType(text string) string {
 	return "info"
 }
 
-// countTokens 使用 tiktoken 准确计算 token 数
-func (p *DocumentProcessor) countTokens(text string) int {
-	// 使用 cl100k_base 编码（GPT-4, text-embedding-3-small 使用的编码）
-	enc, err := tiktoken.GetEncoding("cl100k_base")
-	if err != nil {
-		// 降级到简单估算
-		return len(text) / 4
-	}
-	tokens := enc.Encode(text, nil, nil)
-	return len(tokens)
-}
-
 // ProcessDocumentAsync 异步处理文档（单文档上传）
 // actLogger: 已配置好的任务日志器
 func (p *DocumentProcessor) ProcessDocumentAsync(doc *dbmodel.DocumentUpload, content []byte, docLogger *actlog.TaskLogger) {
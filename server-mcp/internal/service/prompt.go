@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-mcp-context/internal/event"
+	dbmodel "go-mcp-context/internal/model/database"
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/pkg/global"
+
+	"gorm.io/gorm"
+)
+
+// PromptService MCP 提示模板服务
+// 内置模板定义在代码中，数据库中的同名模板优先（可覆盖或禁用内置模板）
+type PromptService struct{}
+
+// 模板中保留的占位符
+const (
+	promptPlaceholderDocs    = "docs"    // 检索到的文档片段
+	promptPlaceholderLibrary = "library" // 解析后的库名
+	promptPlaceholderVersion = "version" // 解析后的版本
+)
+
+// builtinPrompts 内置提示模板
+var builtinPrompts = []dbmodel.PromptTemplate{
+	{
+		Name:        "explain-usage",
+		Title:       "Explain library usage",
+		Description: "Explain how to accomplish a task with a library, grounded in its indexed documentation",
+		Arguments: dbmodel.PromptArguments{
+			{Name: "library", Description: "Library name or ID", Required: true},
+			{Name: "task", Description: "What you want to do, e.g. 'add request logging middleware'", Required: true},
+			{Name: "version", Description: "Library version (optional, defaults to the library's default version)"},
+		},
+		DocsTopic: "{{task}}",
+		Template: "Explain how to {{task}} with {{library}}@{{version}}.\n\n" +
+			"Base the answer on the documentation excerpts below. Prefer the APIs they show, include a complete code example, " +
+			"and say so explicitly if the excerpts do not cover the task.\n\n" +
+			"## Documentation: {{library}}@{{version}}\n\n{{docs}}",
+		Enabled: true,
+	},
+	{
+		Name:        "migrate-version",
+		Title:       "Migrate between library versions",
+		Description: "Migrate code from one version of a library to another using the target version's documentation",
+		Arguments: dbmodel.PromptArguments{
+			{Name: "library", Description: "Library name or ID", Required: true},
+			{Name: "from", Description: "Current version", Required: true},
+			{Name: "to", Description: "Target version", Required: true},
+			{Name: "code", Description: "Code to migrate (optional)"},
+		},
+		DocsTopic:   "migration, upgrade guide, breaking changes, deprecated",
+		DocsVersion: "{{to}}",
+		Template: "Migrate code using {{library}} from version {{from}} to {{to}}.\n\n" +
+			"List the breaking changes that affect the code, then give the migrated code. " +
+			"Use only APIs that exist in {{to}} according to the documentation below.\n\n" +
+			"## Code\n\n{{code}}\n\n" +
+			"## Documentation: {{library}}@{{to}}\n\n{{docs}}",
+		Enabled: true,
+	},
+	{
+		Name:        "review-snippet",
+		Title:       "Review code against best practices",
+		Description: "Review a code snippet against the library's documented best practices",
+		Arguments: dbmodel.PromptArguments{
+			{Name: "library", Description: "Library name or ID", Required: true},
+			{Name: "code", Description: "Code snippet to review", Required: true},
+			{Name: "version", Description: "Library version (optional, defaults to the library's default version)"},
+		},
+		DocsTopic: "best practices, common mistakes, configuration",
+		Template: "Review the following code that uses {{library}}@{{version}}.\n\n" +
+			"Point out misuse, deprecated APIs and deviations from the documented best practices, " +
+			"and suggest concrete fixes with references to the documentation below.\n\n" +
+			"## Code\n\n{{code}}\n\n" +
+			"## Documentation: {{library}}@{{version}}\n\n{{docs}}",
+		Enabled: true,
+	},
+}
+
+// ========== REST 管理 ==========
+
+// List 获取提示模板列表（仅数据库中的模板）
+func (s *PromptService) List(req *request.PromptList) (*response.PageResult, error) {
+	var prompts []dbmodel.PromptTemplate
+	var total int64
+
+	db := global.DB.Model(&dbmodel.PromptTemplate{})
+	if req.Name != nil && *req.Name != "" {
+		db = db.Where("name LIKE ?", "%"+*req.Name+"%")
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page := req.Page
+	pageSize := req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	if err := db.Order("name ASC").Offset(offset).Limit(pageSize).Find(&prompts).Error; err != nil {
+		return nil, err
+	}
+
+	return &response.PageResult{
+		List:     prompts,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetByID 根据 ID 获取提示模板
+func (s *PromptService) GetByID(id uint) (*dbmodel.PromptTemplate, error) {
+	var prompt dbmodel.PromptTemplate
+	if err := global.DB.First(&prompt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &prompt, nil
+}
+
+// Create 创建提示模板
+func (s *PromptService) Create(req *request.PromptCreate) (*dbmodel.PromptTemplate, error) {
+	var count int64
+	if err := global.DB.Model(&dbmodel.PromptTemplate{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrAlreadyExists
+	}
+
+	prompt := &dbmodel.PromptTemplate{
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		Arguments:   req.Arguments,
+		Template:    req.Template,
+		DocsTopic:   req.DocsTopic,
+		DocsVersion: req.DocsVersion,
+		DocsMode:    req.DocsMode,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+		CreatedBy:   req.CreatedBy,
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(prompt).Error; err != nil {
+			return err
+		}
+		// enabled 列有默认值 true，GORM 创建时会忽略零值 false，需要单独写入
+		if !prompt.Enabled {
+			return tx.Model(prompt).Update("enabled", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event.Publish(event.Event{Topic: event.TopicPromptsChanged})
+	return prompt, nil
+}
+
+// Update 更新提示模板（名称不可修改）
+func (s *PromptService) Update(id uint, req *request.PromptUpdate) (*dbmodel.PromptTemplate, error) {
+	prompt, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"title":        req.Title,
+		"description":  req.Description,
+		"arguments":    dbmodel.PromptArguments(req.Arguments),
+		"template":     req.Template,
+		"docs_topic":   req.DocsTopic,
+		"docs_version": req.DocsVersion,
+		"docs_mode":    req.DocsMode,
+	}
+	if req.Enabled != nil {
+		updates["enabled"] = *req.Enabled
+	}
+
+	if err := global.DB.Model(prompt).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	event.Publish(event.Event{Topic: event.TopicPromptsChanged})
+	return s.GetByID(id)
+}
+
+// Delete 删除提示模板（物理删除，释放名称）
+func (s *PromptService) Delete(id uint) error {
+	result := global.DB.Unscoped().Delete(&dbmodel.PromptTemplate{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	event.Publish(event.Event{Topic: event.TopicPromptsChanged})
+	return nil
+}
+
+// ========== MCP prompts ==========
+
+// ListMCPPrompts 获取 prompts/list 展示的提示列表（内置 + 数据库，数据库同名优先）
+func (s *PromptService) ListMCPPrompts() ([]response.MCPPrompt, error) {
+	var stored []dbmodel.PromptTemplate
+	if err := global.DB.Order("name ASC").Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	overridden := make(map[string]bool, len(stored))
+	prompts := make([]response.MCPPrompt, 0, len(builtinPrompts)+len(stored))
+	for i := range stored {
+		overridden[stored[i].Name] = true
+		if stored[i].Enabled {
+			prompts = append(prompts, toMCPPrompt(&stored[i]))
+		}
+	}
+	for i := range builtinPrompts {
+		if !overridden[builtinPrompts[i].Name] {
+			prompts = append(prompts, toMCPPrompt(&builtinPrompts[i]))
+		}
+	}
+
+	return prompts, nil
+}
+
+// GetMCPPrompt 渲染提示（prompts/get）
+// 参数 library 解析为库，按模板的 DocsTopic 检索文档后填充 {{docs}}
+func (s *PromptService) GetMCPPrompt(ctx context.Context, name string, args map[string]string) (*response.MCPPromptResult, error) {
+	tmpl, err := s.findPrompt(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arg := range tmpl.Arguments {
+		if arg.Required && strings.TrimSpace(args[arg.Name]) == "" {
+			return nil, fmt.Errorf("%w: missing required argument %s", ErrInvalidParams, arg.Name)
+		}
+	}
+
+	values := make(map[string]string, len(args)+3)
+	for k, v := range args {
+		values[k] = v
+	}
+
+	// 解析库和版本
+	if libraryArg := values[promptPlaceholderLibrary]; libraryArg != "" {
+		library, err := resolvePromptLibrary(ctx, libraryArg)
+		if err != nil {
+			return nil, err
+		}
+		values[promptPlaceholderLibrary] = library.Name
+		if values[promptPlaceholderVersion] == "" {
+			values[promptPlaceholderVersion] = library.DefaultVersion
+		}
+
+		// 检索文档
+		if tmpl.DocsTopic != "" {
+			docsVersion := tmpl.DocsVersion
+			if docsVersion == "" {
+				docsVersion = "{{" + promptPlaceholderVersion + "}}"
+			}
+
+			docs, err := NewMCPService().GetLibraryDocsWithContext(ctx, &request.MCPGetLibraryDocs{
+				LibraryID: library.ID,
+				Version:   renderPromptTemplate(docsVersion, values),
+				Topic:     renderPromptTemplate(tmpl.DocsTopic, values),
+				Mode:      tmpl.DocsMode,
+				Page:      1,
+			})
+			if err != nil {
+				return nil, err
+			}
+			values[promptPlaceholderDocs] = renderDocumentsMarkdown(docs.Documents)
+		}
+	}
+	if values[promptPlaceholderDocs] == "" {
+		values[promptPlaceholderDocs] = "_No matching documentation found._"
+	}
+
+	return &response.MCPPromptResult{
+		Description: tmpl.Description,
+		Messages: []response.MCPPromptMessage{
+			{
+				Role: "user",
+				Content: response.MCPTextContent{
+					Type: "text",
+					Text: renderPromptTemplate(tmpl.Template, values),
+				},
+			},
+		},
+	}, nil
+}
+
+// findPrompt 查找提示模板（数据库优先，其次内置；禁用的模板视为不存在）
+func (s *PromptService) findPrompt(name string) (*dbmodel.PromptTemplate, error) {
+	var stored dbmodel.PromptTemplate
+	err := global.DB.Where("name = ?", name).First(&stored).Error
+	if err == nil {
+		if !stored.Enabled {
+			return nil, ErrNotFound
+		}
+		return &stored, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	for i := range builtinPrompts {
+		if builtinPrompts[i].Name == name {
+			return &builtinPrompts[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// resolvePromptLibrary 将参数解析为库：支持库 ID、精确库名，否则取库搜索的最佳匹配
+func resolvePromptLibrary(ctx context.Context, value string) (*dbmodel.Library, error) {
+	libraryService := &LibraryService{}
+
+	if id, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return libraryService.GetByID(uint(id))
+	}
+	if library, err := libraryService.GetByName(value); err == nil {
+		return library, nil
+	}
+
+	result, err := NewMCPService().SearchLibrariesWithContext(ctx, &request.MCPSearchLibraries{LibraryName: value})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Libraries) == 0 {
+		return nil, fmt.Errorf("%w: library %s", ErrNotFound, value)
+	}
+	return libraryService.GetByID(result.Libraries[0].LibraryID)
+}
+
+// renderPromptTemplate 将 {{name}} 占位符替换为参数值（未提供的参数替换为空）
+func renderPromptTemplate(tmpl string, values map[string]string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(tmpl, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(tmpl[start:], "}}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(tmpl[:start])
+		name := strings.TrimSpace(tmpl[start+2 : start+end])
+		b.WriteString(values[name])
+		tmpl = tmpl[start+end+2:]
+	}
+	b.WriteString(tmpl)
+	return b.String()
+}
+
+// toMCPPrompt 转换为 prompts/list 格式
+func toMCPPrompt(tmpl *dbmodel.PromptTemplate) response.MCPPrompt {
+	args := make([]response.MCPPromptArgument, 0, len(tmpl.Arguments))
+	for _, arg := range tmpl.Arguments {
+		args = append(args, response.MCPPromptArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+		})
+	}
+	return response.MCPPrompt{
+		Name:        tmpl.Name,
+		Title:       tmpl.Title,
+		Description: tmpl.Description,
+		Arguments:   args,
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"go-mcp-context/pkg/global"
 
 	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
 )
 
 const (
@@ -24,6 +25,8 @@ const (
 	SearchCacheTTL = 24 * time.Hour
 	// 搜索结果缓存 key 前缀
 	SearchCachePrefix = "search:topic:"
+	// 单个 topic 检索的进度阶段数（生成向量、向量检索、关键词检索）
+	searchStagesPerTopic = 3
 )
 
 type SearchService struct{}
@@ -39,14 +42,17 @@ func (s *SearchService) InvalidateLibraryCache(libraryID uint, version string) e
 	return global.Cache.InvalidateTags([]string{tag})
 }
 
-// 混合搜索权重 - 使用RRF算法
+// 混合搜索权重默认值 - 使用RRF算法（可通过 search 配置和库级 search_weights 覆盖）
 const (
 	VectorRRFWeight = 0.7 // 向量搜索RRF权重
 	BM25RRFWeight   = 0.3 // BM25搜索RRF权重
-	HotWeight       = 0.2 // 热度权重（保持不变）
+	HotWeight       = 0.2 // 热度权重
 
 	// RRF 常量
 	RRFConstant = 60 // Elasticsearch 默认值，较高值让低排名文档也有影响力
+
+	// 向量、BM25 检索各自的候选数
+	DefaultSearchTopK = 50
 )
 
 // searchCandidate 搜索候选项（内部使用）
@@ -61,8 +67,11 @@ type searchCandidate struct {
 
 // SearchDocuments 搜索文档
 func (s *SearchService) SearchDocuments(req *request.Search) (*response.SearchResult, error) {
-	ctx := context.Background()
+	return s.SearchDocumentsWithContext(context.Background(), req)
+}
 
+// SearchDocumentsWithContext 搜索文档（ctx 可携带 MCPLogger，向客户端推送检索过程日志）
+func (s *SearchService) SearchDocumentsWithContext(ctx context.Context, req *request.Search) (*response.SearchResult, error) {
 	// 参数默认值
 	page := req.Page
 	limit := req.Limit
@@ -80,39 +89,52 @@ func (s *SearchService) SearchDocuments(req *request.Search) (*response.SearchRe
 	topics := splitTopics(req.Query)
 
 	var candidates []searchCandidate
+	var expansions []response.QueryExpansion
 	var err error
 
+	// 生效的检索权重（全局配置 + 库级覆盖）
+	profile := s.resolveSearchProfile(ctx, req)
+
+	// 每个 topic 三个阶段：生成向量、向量检索、关键词检索
+	mcpProgress(ctx).AddTotal(max(len(topics), 1) * searchStagesPerTopic)
+
 	if len(topics) <= 1 {
 		// 单个 topic，使用混合RRF搜索
-		candidates, err = s.searchSingleTopic(ctx, req, req.Query)
+		var result *topicSearchResult
+		if result, err = s.searchSingleTopic(ctx, req, profile, req.Query); err == nil {
+			candidates = result.Candidates
+			if result.Expansion != nil {
+				expansions = []response.QueryExpansion{*result.Expansion}
+			}
+		}
 	} else {
 		// 多个 topic，并行搜索 + RRF 合并
-		candidates, err = s.searchMultiTopicsWithRRF(ctx, req, topics)
+		candidates, expansions, err = s.searchMultiTopicsWithRRF(ctx, req, profile, topics)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	// 5. 分页返回
+	// 5. 分页返回（指定 token 预算时按预算装填每页，否则按条数分页）
 	total := len(candidates)
-	start := (page - 1) * limit
-	end := start + limit
-	if start >= total {
-		return &response.SearchResult{
-			Results: []response.SearchResultItem{},
-			Total:   int64(total),
-			Page:    page,
-			Limit:   limit,
-			HasMore: false,
-		}, nil
-	}
-	if end > total {
-		end = total
-	}
-
-	results := make([]response.SearchResultItem, 0, end-start)
-	chunkIDs := make([]uint, 0, end-start)
-	for _, c := range candidates[start:end] {
+	var window []packedCandidate
+	var hasMore bool
+	if req.TokenBudget > 0 {
+		window, hasMore = packCandidatesByTokens(candidates, req.TokenBudget, page)
+		limit = len(window)
+	} else {
+		start := (page - 1) * limit
+		end := min(start+limit, total)
+		for i := start; i < end; i++ {
+			window = append(window, packedCandidate{searchCandidate: candidates[i], Tokens: candidates[i].Chunk.Tokens})
+		}
+		hasMore = end < total
+	}
+
+	results := make([]response.SearchResultItem, 0, len(window))
+	chunkIDs := make([]uint, 0, len(window))
+	tokensUsed := 0
+	for _, c := range window {
 		item := response.SearchResultItem{
 			ChunkID:     c.Chunk.ID,
 			UploadID:    c.Chunk.UploadID,
@@ -124,15 +146,25 @@ func (s *SearchService) SearchDocuments(req *request.Search) (*response.SearchRe
 			Source:      c.Chunk.Source,
 			Language:    c.Chunk.Language, // code mode: 代码语言, info mode: 空
 			Code:        c.Chunk.Code,     // code mode: 代码内容, info mode: 空
-			Tokens:      c.Chunk.Tokens,
+			Tokens:      c.Tokens,
+			Truncated:   c.Truncated,
 			Relevance:   c.FinalScore,
 		}
 		// info 类型的块返回 content（chunk_text）
 		if c.Chunk.ChunkType == "info" {
 			item.Content = c.Chunk.ChunkText
 		}
+		// 超出预算被截断的片段使用截断后的正文
+		if c.Truncated {
+			if c.Chunk.ChunkType == "info" {
+				item.Content = c.Text
+			} else {
+				item.Code = c.Text
+			}
+		}
 		results = append(results, item)
 		chunkIDs = append(chunkIDs, c.Chunk.ID)
+		tokensUsed += c.Tokens
 	}
 
 	// 异步更新 access_count
@@ -145,33 +177,35 @@ func (s *SearchService) SearchDocuments(req *request.Search) (*response.SearchRe
 	}
 
 	return &response.SearchResult{
-		Results: results,
-		Total:   int64(total),
-		Page:    page,
-		Limit:   limit,
-		HasMore: end < total,
+		Results:    results,
+		Total:      int64(total),
+		Page:       page,
+		Limit:      limit,
+		HasMore:    hasMore,
+		TokensUsed: tokensUsed,
+
+		QueryExpansion: expansions,
 	}, nil
 }
 
 // vectorSearch 向量搜索
-func (s *SearchService) vectorSearch(ctx context.Context, libraryID uint, queryVector []float32, mode string, version string, limit int) ([]searchCandidate, error) {
+func (s *SearchService) vectorSearch(ctx context.Context, req *request.Search, queryVector []float32, limit int) ([]searchCandidate, error) {
 	var chunks []struct {
 		dbmodel.DocumentChunk
 		Distance float64 `gorm:"column:distance"`
 		DocTitle string  `gorm:"column:doc_title"`
 	}
 
-	query := global.DB.Model(&dbmodel.DocumentChunk{}).
+	query := global.DB.WithContext(ctx).Model(&dbmodel.DocumentChunk{}).
 		Select("document_chunks.*, document_uploads.title as doc_title, embedding <=> ? as distance", pgvector.NewVector(queryVector)).
 		Joins("LEFT JOIN document_uploads ON document_uploads.id = document_chunks.upload_id").
-		Where("document_chunks.status = ? AND document_chunks.deleted_at IS NULL", "active").
-		Where("document_chunks.library_id = ?", libraryID).
-		Where("document_chunks.version = ?", version)
+		Where("document_chunks.status = ? AND document_chunks.deleted_at IS NULL", "active")
+	query = scopeChunks(query, req)
 
 	// mode 过滤：code 搜索 code 类型，info 搜索 info 类型
-	if mode == "code" {
+	if req.Mode == "code" {
 		query = query.Where("document_chunks.chunk_type = ?", "code")
-	} else if mode == "info" {
+	} else if req.Mode == "info" {
 		query = query.Where("document_chunks.chunk_type = ?", "info")
 	}
 
@@ -201,7 +235,7 @@ func (s *SearchService) vectorSearch(ctx context.Context, libraryID uint, queryV
 }
 
 // bm25Search BM25 关键词搜索
-func (s *SearchService) bm25Search(ctx context.Context, libraryID uint, query string, mode string, version string, limit int) ([]searchCandidate, error) {
+func (s *SearchService) bm25Search(ctx context.Context, req *request.Search, query string, limit int) ([]searchCandidate, error) {
 	var chunks []struct {
 		dbmodel.DocumentChunk
 		Rank     float64 `gorm:"column:rank"`
@@ -209,20 +243,19 @@ func (s *SearchService) bm25Search(ctx context.Context, libraryID uint, query st
 	}
 
 	// 使用 PostgreSQL 全文搜索
-	sqlQuery := global.DB.Model(&dbmodel.DocumentChunk{}).
+	sqlQuery := global.DB.WithContext(ctx).Model(&dbmodel.DocumentChunk{}).
 		Select("document_chunks.*, document_uploads.title as doc_title, ts_rank(document_chunks.chunk_tsvector_simple, plainto_tsquery('simple', ?)) as rank", query).
 		Joins("LEFT JOIN document_uploads ON document_uploads.id = document_chunks.upload_id").
 		Where("document_chunks.status = ? AND document_chunks.deleted_at IS NULL", "active").
-		Where("document_chunks.library_id = ?", libraryID).
-		Where("document_chunks.version = ?", version).
 		Where("document_chunks.chunk_tsvector_simple @@ plainto_tsquery('simple', ?)", query).
 		Order("rank DESC").
 		Limit(limit)
+	sqlQuery = scopeChunks(sqlQuery, req)
 
 	// mode 过滤：code 搜索 code 类型，info 搜索 info 类型
-	if mode == "code" {
+	if req.Mode == "code" {
 		sqlQuery = sqlQuery.Where("document_chunks.chunk_type = ?", "code")
-	} else if mode == "info" {
+	} else if req.Mode == "info" {
 		sqlQuery = sqlQuery.Where("document_chunks.chunk_type = ?", "info")
 	}
 
@@ -242,14 +275,27 @@ func (s *SearchService) bm25Search(ctx context.Context, libraryID uint, query st
 	return results, nil
 }
 
+// scopeChunks 限定检索范围：跨库检索按 (library_id, version) 组合过滤，否则按单个库版本过滤
+func scopeChunks(query *gorm.DB, req *request.Search) *gorm.DB {
+	if len(req.Scopes) == 0 {
+		return query.Where("document_chunks.library_id = ? AND document_chunks.version = ?", req.LibraryID, req.Version)
+	}
+
+	pairs := make([][]interface{}, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		pairs = append(pairs, []interface{}{scope.LibraryID, scope.Version})
+	}
+	return query.Where("(document_chunks.library_id, document_chunks.version) IN ?", pairs)
+}
+
 // mergeAndRerank 合并去重并重排序 - 使用RRF算法
-func (s *SearchService) mergeAndRerank(vectorResults, bm25Results []searchCandidate) []searchCandidate {
+func (s *SearchService) mergeAndRerank(profile searchProfile, vectorResults, bm25Results []searchCandidate) []searchCandidate {
 	// 对得分进行归一化（可选，RRF主要基于排名）
 	s.normalizeScoresMinMax(vectorResults, "vector")
 	s.normalizeScoresMinMax(bm25Results, "bm25")
 
 	// 使用RRF算法合并结果
-	return s.hybridRRF(vectorResults, bm25Results)
+	return s.hybridRRF(profile, vectorResults, bm25Results)
 }
 
 // extractDeepestTitle 从 Metadata 提取最深层级的标题
@@ -290,59 +336,234 @@ func splitTopics(query string) []string {
 	return topics
 }
 
-// searchSingleTopic 单个 topic 搜索（带缓存）
-func (s *SearchService) searchSingleTopic(ctx context.Context, req *request.Search, topic string) ([]searchCandidate, error) {
-	// 生成缓存 key: search:topic:{library_id}:{version}:{mode}:{topic_hash}
-	cacheKey := s.buildSearchCacheKey(req.LibraryID, req.Version, req.Mode, topic)
+// topicSearchResult 单个 topic 的检索结果（整体写入搜索缓存）
+type topicSearchResult struct {
+	Candidates []searchCandidate
+	Expansion  *response.QueryExpansion // 查询扩展情况（未启用查询扩展时为 nil）
 
-	// 生成缓存 tag: library:{library_id}:{version}
-	cacheTag := s.buildSearchCacheTag(req.LibraryID, req.Version)
+	RerankFallback bool `json:"-"` // 重排序超时或失败，回退到 RRF 顺序
+}
 
-	// 定义搜索函数
-	fetchFunc := s.buildSearchFunc(ctx, req, topic)
+// degraded 查询扩展或重排序失败时的降级结果不保留在缓存中，下次请求重新尝试
+func (r *topicSearchResult) degraded() bool {
+	return r.RerankFallback || (r.Expansion != nil && !r.Expansion.Applied)
+}
+
+// searchSingleTopic 单个 topic 搜索（带缓存）
+func (s *SearchService) searchSingleTopic(ctx context.Context, req *request.Search, profile searchProfile, topic string) (*topicSearchResult, error) {
+	// 生成缓存 key: search:topic:{library_id}:{version}:{mode}:{profile}:{topic_hash}
+	// 生成缓存 tag: library:{library_id}:{version}（跨库检索为每个库版本各一个 tag）
+	cacheKey, cacheTags := s.buildSearchCacheKeyAndTags(req, profile, topic)
+
+	// 定义搜索函数（执行即表示缓存未命中）
+	cacheHit := true
+	searchFunc := s.buildSearchFunc(ctx, req, profile, topic)
+	fetchFunc := func() (*topicSearchResult, error) {
+		cacheHit = false
+		return searchFunc()
+	}
 
 	// 使用 GetOrSetWithTags 模式：缓存 key 包含 tag version，tag 失效时旧缓存自动失效
-	return cache.GetOrSetWithTags(global.Cache, cacheKey, []string{cacheTag}, SearchCacheTTL, fetchFunc)
+	result, err := cache.GetOrSetWithTags(global.Cache, cacheKey, cacheTags, SearchCacheTTL, fetchFunc)
+	if err != nil {
+		return nil, err
+	}
+	if cacheHit {
+		mcpProgress(ctx).Advance(searchStagesPerTopic, fmt.Sprintf("%s: cache hit", topic))
+	} else if result.degraded() && global.Cache != nil {
+		// 查询扩展失败（按原 topic 检索）或重排序失败（RRF 顺序）的结果不保留，下次请求重新尝试
+		if key, err := cache.BuildTaggedKey(global.Cache, cacheKey, cacheTags); err == nil {
+			_ = global.Cache.Delete(key)
+		}
+	}
+	mcpLog(ctx, "debug", "search", map[string]interface{}{
+		"topic":      topic,
+		"cacheHit":   cacheHit,
+		"candidates": len(result.Candidates),
+	})
+	return result, nil
 }
 
 // buildSearchFunc 构建搜索函数（用于 GetOrSet）
-func (s *SearchService) buildSearchFunc(ctx context.Context, req *request.Search, topic string) func() ([]searchCandidate, error) {
-	return func() ([]searchCandidate, error) {
-		return s.executeSearch(ctx, req, topic)
+func (s *SearchService) buildSearchFunc(ctx context.Context, req *request.Search, profile searchProfile, topic string) func() (*topicSearchResult, error) {
+	return func() (*topicSearchResult, error) {
+		return s.executeSearch(ctx, req, profile, topic)
 	}
 }
 
 // executeSearch 执行实际的搜索逻辑
-func (s *SearchService) executeSearch(ctx context.Context, req *request.Search, topic string) ([]searchCandidate, error) {
+func (s *SearchService) executeSearch(ctx context.Context, req *request.Search, profile searchProfile, topic string) (*topicSearchResult, error) {
+	result := &topicSearchResult{}
+
+	// 1. LLM 查询扩展（可选）：扩展出的子查询各自混合检索，假设答案段落（HyDE）用于向量检索，再与原 topic 的结果 RRF 合并
+	var expansion *queryExpansion
+	if profile.Expansion != "" {
+		result.Expansion = &response.QueryExpansion{Topic: topic}
+		exp, err := s.expandTopic(ctx, profile, topic)
+		if err != nil {
+			global.Log.Warn(fmt.Sprintf("query expansion failed, searching the raw topic: %s, error: %v", topic, err))
+			mcpLog(ctx, "warning", "search", map[string]interface{}{
+				"topic":     topic,
+				"expansion": "failed",
+				"error":     err.Error(),
+			})
+		} else {
+			expansion = exp
+			result.Expansion.Applied = true
+			result.Expansion.Queries = exp.Queries
+			result.Expansion.HyDE = exp.Passage != ""
+			mcpLog(ctx, "debug", "search", map[string]interface{}{
+				"topic":   topic,
+				"queries": exp.Queries,
+				"hyde":    exp.Passage != "",
+			})
+		}
+	}
+
+	// 2. 原 topic 混合检索
+	var merged []searchCandidate
+	var err error
+	if expansion == nil {
+		merged, err = s.hybridSearch(ctx, req, profile, topic)
+	} else {
+		merged, err = s.expandedSearch(ctx, req, profile, topic, expansion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 对 RRF 前 TopN 个候选做语义重排序（未配置、超时或失败时保持 RRF 顺序）
+	result.Candidates, result.RerankFallback = s.rerankCandidates(ctx, topic, merged)
+	return result, nil
+}
+
+// hybridSearch 单个查询的混合检索：向量检索 + BM25 检索，RRF 合并
+func (s *SearchService) hybridSearch(ctx context.Context, req *request.Search, profile searchProfile, query string) ([]searchCandidate, error) {
 	// 1. 生成查询向量（CachedEmbeddingService 自带缓存）
-	queryVector, err := global.Embedding.Embed(topic)
+	queryVector, err := global.Embedding.EmbedWithContext(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+		return nil, fmt.Errorf("%w: failed to generate embedding: %w", ErrEmbeddingUnavailable, err)
 	}
+	progress := mcpProgress(ctx)
+	progress.Advance(1, fmt.Sprintf("%s: embedding generated", query))
 
-	// 2. 执行向量搜索 (Top-50)
-	vectorResults, err := s.vectorSearch(ctx, req.LibraryID, queryVector, req.Mode, req.Version, 50)
+	// 2. 执行向量搜索 (Top-K，默认 50)
+	vectorResults, err := s.vectorSearch(ctx, req, queryVector, profile.VectorTopK)
 	if err != nil {
 		return nil, fmt.Errorf("vector search failed: %w", err)
 	}
+	progress.Advance(1, fmt.Sprintf("%s: vector search done (%d candidates)", query, len(vectorResults)))
 
-	// 3. 执行 BM25 关键词搜索 (Top-50)
-	bm25Results, err := s.bm25Search(ctx, req.LibraryID, topic, req.Mode, req.Version, 50)
+	// 3. 执行 BM25 关键词搜索 (Top-K，默认 50)
+	bm25Results, err := s.bm25Search(ctx, req, query, profile.BM25TopK)
 	if err != nil {
 		return nil, fmt.Errorf("bm25 search failed: %w", err)
 	}
+	progress.Advance(1, fmt.Sprintf("%s: keyword search done (%d candidates)", query, len(bm25Results)))
 
 	// 4. 合并去重并重排序
-	return s.mergeAndRerank(vectorResults, bm25Results), nil
+	merged := s.mergeAndRerank(profile, vectorResults, bm25Results)
+	mcpLog(ctx, "debug", "search", map[string]interface{}{
+		"topic":  query,
+		"vector": len(vectorResults),
+		"bm25":   len(bm25Results),
+		"merged": len(merged),
+	})
+	return merged, nil
+}
+
+// expandedSearch 原 topic 与扩展查询并行检索，结果通过 reciprocalRankFusion 合并
+// 原 topic 检索失败时返回错误；扩展查询失败只记录日志
+func (s *SearchService) expandedSearch(ctx context.Context, req *request.Search, profile searchProfile, topic string, expansion *queryExpansion) ([]searchCandidate, error) {
+	// 每个扩展查询三个阶段，HyDE 两个阶段（生成向量、向量检索）
+	hydeStages := 0
+	if expansion.Passage != "" {
+		hydeStages = 2
+	}
+	mcpProgress(ctx).AddTotal(len(expansion.Queries)*searchStagesPerTopic + hydeStages)
+
+	queries := append([]string{topic}, expansion.Queries...)
+	lists := make([][]searchCandidate, len(queries)+1)
+	errs := make([]error, len(queries)+1)
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		wg.Add(1)
+		go func(i int, query string) {
+			defer wg.Done()
+			lists[i], errs[i] = s.hybridSearch(ctx, req, profile, query)
+		}(i, query)
+	}
+	if expansion.Passage != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lists[len(queries)], errs[len(queries)] = s.hydeSearch(ctx, req, profile, topic, expansion.Passage)
+		}()
+	}
+	wg.Wait()
+
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	var resultLists [][]searchCandidate
+	for i, list := range lists {
+		if errs[i] != nil {
+			global.Log.Warn(fmt.Sprintf("expanded query search failed: %s, error: %v", topic, errs[i]))
+			continue
+		}
+		if len(list) > 0 {
+			resultLists = append(resultLists, list)
+		}
+	}
+	if len(resultLists) == 0 {
+		return nil, nil
+	}
+	return s.reciprocalRankFusion(profile.RRFConstant, resultLists), nil
+}
+
+// hydeSearch 用假设答案段落的向量检索（HyDE），段落比简短的 topic 更接近文档片段的向量
+func (s *SearchService) hydeSearch(ctx context.Context, req *request.Search, profile searchProfile, topic, passage string) ([]searchCandidate, error) {
+	passageVector, err := global.Embedding.EmbedWithContext(ctx, passage)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to generate embedding: %w", ErrEmbeddingUnavailable, err)
+	}
+	progress := mcpProgress(ctx)
+	progress.Advance(1, fmt.Sprintf("%s: hypothetical passage embedded", topic))
+
+	results, err := s.vectorSearch(ctx, req, passageVector, profile.VectorTopK)
+	if err != nil {
+		return nil, fmt.Errorf("vector search failed: %w", err)
+	}
+	progress.Advance(1, fmt.Sprintf("%s: hypothetical passage search done (%d candidates)", topic, len(results)))
+	return results, nil
 }
 
 // buildSearchCacheKey 构建搜索缓存 key
-// 格式: search:topic:{library_id}:{version}:{mode}:{topic_hash}
-// 参数顺序与 key 格式一致
-func (s *SearchService) buildSearchCacheKey(libraryID uint, version, mode, topic string) string {
+// 格式: search:topic:{library_id}:{version}:{mode}:{profile}:{topic_hash}
+// 参数顺序与 key 格式一致，profile 为检索权重的短哈希
+func (s *SearchService) buildSearchCacheKey(libraryID uint, version, mode, profile, topic string) string {
 	hash := md5.Sum([]byte(topic))
 	topicHash := hex.EncodeToString(hash[:])
-	return fmt.Sprintf("%s%d:%s:%s:%s", SearchCachePrefix, libraryID, version, mode, topicHash)
+	return fmt.Sprintf("%s%d:%s:%s:%s:%s", SearchCachePrefix, libraryID, version, mode, profile, topicHash)
+}
+
+// buildSearchCacheKeyAndTags 构建检索请求的缓存 key 与 tag
+// 跨库检索的 key 以 0 作为库ID、以库版本组合的哈希作为版本，任一库版本的文档变化都会使其失效
+func (s *SearchService) buildSearchCacheKeyAndTags(req *request.Search, profile searchProfile, topic string) (string, []string) {
+	if len(req.Scopes) == 0 {
+		return s.buildSearchCacheKey(req.LibraryID, req.Version, req.Mode, profile.cacheKey(), topic),
+			[]string{s.buildSearchCacheTag(req.LibraryID, req.Version)}
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	tags := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scopes = append(scopes, fmt.Sprintf("%d@%s", scope.LibraryID, scope.Version))
+		tags = append(tags, s.buildSearchCacheTag(scope.LibraryID, scope.Version))
+	}
+	sort.Strings(scopes)
+	hash := md5.Sum([]byte(strings.Join(scopes, ",")))
+	return s.buildSearchCacheKey(0, "scopes-"+hex.EncodeToString(hash[:]), req.Mode, profile.cacheKey(), topic), tags
 }
 
 // buildSearchCacheTag 构建搜索缓存 tag
@@ -403,8 +624,8 @@ func (s *SearchService) normalizeScoresMinMax(candidates []searchCandidate, scor
 	}
 }
 
-// hybridRRF 使用RRF算法合并向量搜索和BM25搜索结果
-func (s *SearchService) hybridRRF(vectorResults, bm25Results []searchCandidate) []searchCandidate {
+// hybridRRF 使用RRF算法合并向量搜索和BM25搜索结果（权重与 RRF 常量取自 profile）
+func (s *SearchService) hybridRRF(profile searchProfile, vectorResults, bm25Results []searchCandidate) []searchCandidate {
 	// 构建排名映射
 	vectorRanks := make(map[uint]int)
 	bm25Ranks := make(map[uint]int)
@@ -454,17 +675,17 @@ func (s *SearchService) hybridRRF(vectorResults, bm25Results []searchCandidate)
 
 		// 向量搜索贡献
 		if rank, exists := vectorRanks[candidate.Chunk.ID]; exists {
-			rrfScore += VectorRRFWeight / (float64(rank) + float64(RRFConstant))
+			rrfScore += profile.VectorWeight / (float64(rank) + float64(profile.RRFConstant))
 		}
 
 		// BM25搜索贡献
 		if rank, exists := bm25Ranks[candidate.Chunk.ID]; exists {
-			rrfScore += BM25RRFWeight / (float64(rank) + float64(RRFConstant))
+			rrfScore += profile.BM25Weight / (float64(rank) + float64(profile.RRFConstant))
 		}
 
 		// 热度贡献
 		hotScore := float64(candidate.Chunk.AccessCount) / float64(maxAccessCount)
-		rrfScore += HotWeight * hotScore
+		rrfScore += profile.HotWeight * hotScore
 
 		candidate.FinalScore = rrfScore
 		candidate.HotScore = hotScore
@@ -479,13 +700,13 @@ func (s *SearchService) hybridRRF(vectorResults, bm25Results []searchCandidate)
 	return candidates
 }
 
-// searchMultiTopicsWithRRF 多 topic 并行搜索 + RRF 合并
-func (s *SearchService) searchMultiTopicsWithRRF(ctx context.Context, req *request.Search, topics []string) ([]searchCandidate, error) {
+// searchMultiTopicsWithRRF 多 topic 并行搜索 + RRF 合并（同时返回各 topic 的查询扩展情况，按 topic 顺序）
+func (s *SearchService) searchMultiTopicsWithRRF(ctx context.Context, req *request.Search, profile searchProfile, topics []string) ([]searchCandidate, []response.QueryExpansion, error) {
 	// 并行搜索每个 topic
 	type topicResult struct {
-		topic      string
-		candidates []searchCandidate
-		err        error
+		topic  string
+		result *topicSearchResult
+		err    error
 	}
 
 	resultChan := make(chan topicResult, len(topics))
@@ -495,8 +716,8 @@ func (s *SearchService) searchMultiTopicsWithRRF(ctx context.Context, req *reque
 		wg.Add(1)
 		go func(t string) {
 			defer wg.Done()
-			candidates, err := s.searchSingleTopic(ctx, req, t)
-			resultChan <- topicResult{topic: t, candidates: candidates, err: err}
+			result, err := s.searchSingleTopic(ctx, req, profile, t)
+			resultChan <- topicResult{topic: t, result: result, err: err}
 		}(topic)
 	}
 
@@ -508,29 +729,53 @@ func (s *SearchService) searchMultiTopicsWithRRF(ctx context.Context, req *reque
 
 	// 收集结果
 	var allResults [][]searchCandidate
+	expansionByTopic := make(map[string]response.QueryExpansion)
+	var lastErr error
+	failed := 0
 	for result := range resultChan {
 		if result.err != nil {
+			failed++
+			lastErr = result.err
 			// 记录错误但继续处理其他结果
 			global.Log.Warn(fmt.Sprintf("topic search failed: %s, error: %v", result.topic, result.err))
+			mcpLog(ctx, "warning", "search", map[string]interface{}{
+				"topic": result.topic,
+				"error": result.err.Error(),
+			})
 			continue
 		}
-		if len(result.candidates) > 0 {
-			allResults = append(allResults, result.candidates)
+		if result.result.Expansion != nil {
+			expansionByTopic[result.topic] = *result.result.Expansion
+		}
+		if len(result.result.Candidates) > 0 {
+			allResults = append(allResults, result.result.Candidates)
+		}
+	}
+
+	// 所有 topic 都失败时返回错误（如向量服务不可用），避免误报为无结果
+	if failed == len(topics) {
+		return nil, nil, lastErr
+	}
+
+	var expansions []response.QueryExpansion
+	for _, topic := range topics {
+		if expansion, ok := expansionByTopic[topic]; ok {
+			expansions = append(expansions, expansion)
 		}
 	}
 
 	if len(allResults) == 0 {
-		return nil, nil
+		return nil, expansions, nil
 	}
 
 	// 使用 RRF 合并多个结果列表
-	return s.reciprocalRankFusion(allResults), nil
+	return s.reciprocalRankFusion(profile.RRFConstant, allResults), expansions, nil
 }
 
 // reciprocalRankFusion 使用 RRF 算法合并多个排序结果
 // 公式: score(d) = Σ 1 / (k + rank(d))
 // 其中 k 是常量（默认 60），rank 是文档在每个列表中的排名（从 1 开始）
-func (s *SearchService) reciprocalRankFusion(resultLists [][]searchCandidate) []searchCandidate {
+func (s *SearchService) reciprocalRankFusion(k int, resultLists [][]searchCandidate) []searchCandidate {
 	// 用 map 存储每个文档的 RRF 分数
 	rrfScores := make(map[uint]float64)
 	candidateMap := make(map[uint]*searchCandidate)
@@ -539,7 +784,7 @@ func (s *SearchService) reciprocalRankFusion(resultLists [][]searchCandidate) []
 		for rank, candidate := range results {
 			chunkID := candidate.Chunk.ID
 			// RRF 公式: 1 / (k + rank)，rank 从 1 开始
-			rrfScores[chunkID] += 1.0 / float64(RRFConstant+rank+1)
+			rrfScores[chunkID] += 1.0 / float64(k+rank+1)
 
 			// 保存候选项（如果还没有）
 			if _, exists := candidateMap[chunkID]; !exists {
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"strings"
+
+	dbmodel "go-mcp-context/internal/model/database"
+)
+
+// 按 token 预算分页
+//
+// 指定预算时不再按固定条数分页：按相关性顺序依次放入片段，直到预算用完；
+// 放不下的片段截断到剩余预算（剩余预算过少时留到下一页），因此每页至少有一个片段，
+// 超出预算的单个大片段也会以截断形式返回而不是被跳过。第 N 页是从头依次装填得到的第 N 个窗口
+
+// minTruncatedChunkTokens 截断片段的最小 token 数（剩余预算低于该值时不再截断放入，留到下一页）
+const minTruncatedChunkTokens = 100
+
+// packedCandidate 放入当前页的候选项
+type packedCandidate struct {
+	searchCandidate
+	Tokens    int    // 计入预算的 token 数
+	Truncated bool   // 是否被截断
+	Text      string // 截断后的正文（code 片段为代码，info 片段为 chunk_text；未截断时为空）
+}
+
+// packCandidatesByTokens 按 token 预算取第 page 页的候选项
+func packCandidatesByTokens(candidates []searchCandidate, budget, page int) ([]packedCandidate, bool) {
+	start := 0
+	for p := 1; start < len(candidates); p++ {
+		window, next := packTokenWindow(candidates, start, budget)
+		if p == page {
+			return window, next < len(candidates)
+		}
+		start = next
+	}
+	return []packedCandidate{}, false
+}
+
+// packTokenWindow 从 start 开始装填一页，返回该页与下一页的起始位置
+func packTokenWindow(candidates []searchCandidate, start, budget int) ([]packedCandidate, int) {
+	var window []packedCandidate
+	used := 0
+	i := start
+	for ; i < len(candidates); i++ {
+		c := candidates[i]
+		tokens := chunkTokens(&c.Chunk)
+		remaining := budget - used
+		if tokens <= remaining {
+			window = append(window, packedCandidate{searchCandidate: c, Tokens: tokens})
+			used += tokens
+			continue
+		}
+
+		// 放不下：页内第一个片段或剩余预算足够时截断放入，否则留到下一页
+		if len(window) == 0 || remaining >= minTruncatedChunkTokens {
+			text, truncatedTokens := truncateToTokens(chunkBody(&c.Chunk), remaining)
+			window = append(window, packedCandidate{searchCandidate: c, Tokens: truncatedTokens, Truncated: true, Text: text})
+			i++
+		}
+		break
+	}
+	return window, i
+}
+
+// chunkTokens 片段的 token 数（入库时未记录则现场计算）
+func chunkTokens(chunk *dbmodel.DocumentChunk) int {
+	if chunk.Tokens > 0 {
+		return chunk.Tokens
+	}
+	return countTextTokens(chunkBody(chunk))
+}
+
+// chunkBody 片段返回给调用方的正文（code 片段为代码，info 片段为 chunk_text）
+func chunkBody(chunk *dbmodel.DocumentChunk) string {
+	if chunk.ChunkType == "info" {
+		return chunk.ChunkText
+	}
+	return chunk.Code
+}
+
+// truncateToTokens 将文本截断到 maxTokens 以内，尽量在换行处截断；返回截断后的文本及其 token 数
+func truncateToTokens(text string, maxTokens int) (string, int) {
+	if maxTokens <= 0 {
+		return "", 0
+	}
+
+	var truncated string
+	if enc := tokenEncoder(); enc != nil {
+		tokens := enc.Encode(text, nil, nil)
+		if len(tokens) <= maxTokens {
+			return text, len(tokens)
+		}
+		// 截断位置可能落在多字节字符中间
+		truncated = strings.ToValidUTF8(enc.Decode(tokens[:maxTokens]), "")
+	} else {
+		if len(text) <= maxTokens*4 {
+			return text, len(text) / 4
+		}
+		truncated = strings.ToValidUTF8(text[:maxTokens*4], "")
+	}
+
+	// 换行位置不太靠前时在换行处截断，避免截断半行代码
+	if i := strings.LastIndexByte(truncated, '\n'); i > len(truncated)/2 {
+		truncated = truncated[:i]
+	}
+	truncated = strings.TrimRight(truncated, " \t\n")
+	return truncated, countTextTokens(truncated)
+}
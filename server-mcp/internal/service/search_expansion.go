@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-mcp-context/pkg/cache"
+	"go-mcp-context/pkg/global"
+)
+
+// LLM 查询扩展方式（search.query_expansion）
+const (
+	QueryExpansionOff    = "off"
+	QueryExpansionExpand = "expand" // 改写为多个子查询，各自混合检索
+	QueryExpansionHyDE   = "hyde"   // 生成假设答案段落（Hypothetical Document Embeddings），用其向量检索
+	QueryExpansionBoth   = "both"
+)
+
+const (
+	// 查询扩展缓存 key 前缀（按扩展方式和 topic 缓存 LLM 结果）
+	ExpansionCachePrefix = "search:expansion:"
+
+	defaultExpansionQueries = 3
+	maxExpansionQueries     = 5
+	defaultExpansionTimeout = 5 * time.Second
+
+	maxExpansionQueryRunes   = 200
+	maxExpansionPassageRunes = 1500
+)
+
+// queryExpansion LLM 对 topic 的改写结果
+type queryExpansion struct {
+	Queries []string `json:"queries"` // 扩展出的子查询（不含原 topic）
+	Passage string   `json:"passage"` // 假设答案段落（HyDE）
+}
+
+// expandTopic 调用 LLM 扩展 topic（按扩展方式和 topic 缓存，失败不缓存）
+func (s *SearchService) expandTopic(ctx context.Context, profile searchProfile, topic string) (*queryExpansion, error) {
+	if global.LLM == nil {
+		return nil, errors.New("LLM service not configured")
+	}
+
+	hash := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(topic))))
+	key := fmt.Sprintf("%s%s:%d:%s", ExpansionCachePrefix, profile.Expansion, profile.ExpansionQueries, hex.EncodeToString(hash[:]))
+
+	var c cache.Cache
+	if global.Cache != nil {
+		c = global.Cache
+	}
+	return cache.GetOrSet(c, key, SearchCacheTTL, func() (*queryExpansion, error) {
+		timeout := defaultExpansionTimeout
+		if d, err := time.ParseDuration(global.Config.Search.ExpansionTimeout); err == nil && d > 0 {
+			timeout = d
+		}
+		llmCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		reply, err := global.LLM.Chat(llmCtx, buildExpansionPrompt(profile, topic))
+		if err != nil {
+			return nil, fmt.Errorf("query expansion failed: %w", err)
+		}
+		return parseQueryExpansion(reply, profile, topic)
+	})
+}
+
+// parseQueryExpansion 解析 LLM 返回的 JSON，去掉空白、重复和与原 topic 相同的子查询
+func parseQueryExpansion(reply string, profile searchProfile, topic string) (*queryExpansion, error) {
+	// 模型可能在 JSON 前后附带说明文字，只取最外层的 JSON 对象
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("query expansion: no JSON in response")
+	}
+	var output queryExpansion
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &output); err != nil {
+		return nil, fmt.Errorf("query expansion: parse response failed: %w", err)
+	}
+
+	expansion := &queryExpansion{}
+	if profile.Expansion != QueryExpansionHyDE {
+		seen := map[string]bool{strings.ToLower(strings.TrimSpace(topic)): true}
+		for _, query := range output.Queries {
+			query = truncateRunes(strings.TrimSpace(query), maxExpansionQueryRunes)
+			if query == "" || seen[strings.ToLower(query)] {
+				continue
+			}
+			seen[strings.ToLower(query)] = true
+			expansion.Queries = append(expansion.Queries, query)
+			if len(expansion.Queries) == profile.ExpansionQueries {
+				break
+			}
+		}
+	}
+	if profile.Expansion != QueryExpansionExpand {
+		expansion.Passage = truncateRunes(strings.TrimSpace(output.Passage), maxExpansionPassageRunes)
+	}
+
+	if len(expansion.Queries) == 0 && expansion.Passage == "" {
+		return nil, errors.New("query expansion: empty result")
+	}
+	return expansion, nil
+}
+
+// buildExpansionPrompt 构建查询扩展提示词
+func buildExpansionPrompt(profile searchProfile, topic string) string {
+	var b strings.Builder
+	b.WriteString("You help a developer search library documentation. Their search topic is often short or vague.\n\n")
+	fmt.Fprintf(&b, "## Topic\n%s\n\n## Task\n", topic)
+	if profile.Expansion != QueryExpansionHyDE {
+		fmt.Fprintf(&b, "- queries: rewrite the topic into %d specific search queries that cover its most likely meanings, spelling out abbreviations (e.g. \"ctx\" -> \"context\")\n", profile.ExpansionQueries)
+	}
+	if profile.Expansion != QueryExpansionExpand {
+		b.WriteString("- passage: write a short documentation passage (80-150 words, may include a small code example) that would answer the topic\n")
+	}
+	b.WriteString(`
+## Return JSON:
+{"queries": ["..."], "passage": "..."}
+
+## Rules
+- Omit fields that are not requested
+- Keep technical terms in their original language
+- Return strict JSON only, no other content`)
+	return b.String()
+}
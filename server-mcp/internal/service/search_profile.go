@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+
+	dbmodel "go-mcp-context/internal/model/database"
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/pkg/global"
+)
+
+// 库级检索权重取值范围
+const (
+	maxSearchWeight      = 10.0
+	maxSearchRRFConstant = 1000
+	maxSearchTopK        = 200
+)
+
+// searchProfile 生效的混合检索权重：内置默认值 <- search 配置 <- 库级 search_weights
+type searchProfile struct {
+	VectorWeight float64
+	BM25Weight   float64
+	HotWeight    float64
+	RRFConstant  int
+	VectorTopK   int
+	BM25TopK     int
+
+	// 查询扩展（仅全局配置）：Expansion 为空表示不扩展
+	Expansion        string
+	ExpansionQueries int
+}
+
+// defaultSearchProfile 全局检索权重（search 配置，未设置的字段使用内置默认值）
+func defaultSearchProfile() searchProfile {
+	profile := searchProfile{
+		VectorWeight: VectorRRFWeight,
+		BM25Weight:   BM25RRFWeight,
+		HotWeight:    HotWeight,
+		RRFConstant:  RRFConstant,
+		VectorTopK:   DefaultSearchTopK,
+		BM25TopK:     DefaultSearchTopK,
+	}
+	if global.Config == nil {
+		return profile
+	}
+
+	cfg := global.Config.Search
+	if cfg.VectorWeight != nil {
+		profile.VectorWeight = *cfg.VectorWeight
+	}
+	if cfg.BM25Weight != nil {
+		profile.BM25Weight = *cfg.BM25Weight
+	}
+	if cfg.HotWeight != nil {
+		profile.HotWeight = *cfg.HotWeight
+	}
+	if cfg.RRFConstant > 0 {
+		profile.RRFConstant = cfg.RRFConstant
+	}
+	if cfg.VectorTopK > 0 {
+		profile.VectorTopK = cfg.VectorTopK
+	}
+	if cfg.BM25TopK > 0 {
+		profile.BM25TopK = cfg.BM25TopK
+	}
+	switch cfg.QueryExpansion {
+	case QueryExpansionExpand, QueryExpansionHyDE, QueryExpansionBoth:
+		profile.Expansion = cfg.QueryExpansion
+		profile.ExpansionQueries = defaultExpansionQueries
+		if cfg.ExpansionQueries > 0 {
+			profile.ExpansionQueries = min(cfg.ExpansionQueries, maxExpansionQueries)
+		}
+	}
+	return profile
+}
+
+// withOverrides 应用库级权重覆盖
+func (p searchProfile) withOverrides(w *dbmodel.SearchWeights) searchProfile {
+	if w == nil {
+		return p
+	}
+	if w.VectorWeight != nil {
+		p.VectorWeight = *w.VectorWeight
+	}
+	if w.BM25Weight != nil {
+		p.BM25Weight = *w.BM25Weight
+	}
+	if w.HotWeight != nil {
+		p.HotWeight = *w.HotWeight
+	}
+	if w.RRFConstant != nil {
+		p.RRFConstant = *w.RRFConstant
+	}
+	if w.VectorTopK != nil {
+		p.VectorTopK = *w.VectorTopK
+	}
+	if w.BM25TopK != nil {
+		p.BM25TopK = *w.BM25TopK
+	}
+	return p
+}
+
+// cacheKey 权重组合（含查询扩展方式）的短哈希，写入搜索缓存 key，配置变化后立即使用新的缓存
+func (p searchProfile) cacheKey() string {
+	hash := md5.Sum([]byte(fmt.Sprintf("%g:%g:%g:%d:%d:%d:%s:%d",
+		p.VectorWeight, p.BM25Weight, p.HotWeight, p.RRFConstant, p.VectorTopK, p.BM25TopK, p.Expansion, p.ExpansionQueries)))
+	return hex.EncodeToString(hash[:4])
+}
+
+// resolveSearchProfile 解析检索请求生效的权重
+// 单库检索应用该库的 search_weights（优先使用调用方已加载的库）；跨库检索各库的结果在同一次 RRF 中合并，使用全局配置
+func (s *SearchService) resolveSearchProfile(ctx context.Context, req *request.Search) searchProfile {
+	profile := defaultSearchProfile()
+	if len(req.Scopes) > 0 || req.LibraryID == 0 {
+		return profile
+	}
+	if req.Library != nil && req.Library.ID == req.LibraryID {
+		return profile.withOverrides(req.Library.SearchWeights)
+	}
+
+	var library dbmodel.Library
+	if err := global.DB.WithContext(ctx).Select("id", "search_weights").First(&library, req.LibraryID).Error; err != nil {
+		return profile
+	}
+	return profile.withOverrides(library.SearchWeights)
+}
+
+// validateSearchWeights 校验库级检索权重
+func validateSearchWeights(w *dbmodel.SearchWeights) error {
+	if w == nil {
+		return nil
+	}
+	for name, weight := range map[string]*float64{
+		"vector_weight": w.VectorWeight,
+		"bm25_weight":   w.BM25Weight,
+		"hot_weight":    w.HotWeight,
+	} {
+		if weight != nil && (*weight < 0 || *weight > maxSearchWeight) {
+			return fmt.Errorf("%w: %s must be between 0 and %g", ErrInvalidParams, name, maxSearchWeight)
+		}
+	}
+	if w.RRFConstant != nil && (*w.RRFConstant < 1 || *w.RRFConstant > maxSearchRRFConstant) {
+		return fmt.Errorf("%w: rrf_constant must be between 1 and %d", ErrInvalidParams, maxSearchRRFConstant)
+	}
+	for name, topK := range map[string]*int{
+		"vector_top_k": w.VectorTopK,
+		"bm25_top_k":   w.BM25TopK,
+	} {
+		if topK != nil && (*topK < 1 || *topK > maxSearchTopK) {
+			return fmt.Errorf("%w: %s must be between 1 and %d", ErrInvalidParams, name, maxSearchTopK)
+		}
+	}
+	return nil
+}
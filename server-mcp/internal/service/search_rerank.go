@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 默认参与重排序的候选数
+	defaultRerankTopN = 20
+	// 默认重排序超时，超时回退到 RRF 顺序
+	defaultRerankTimeout = 3 * time.Second
+	// 单个候选送入重排序的最大字符数
+	rerankDocumentMaxRunes = 2000
+)
+
+// rerankCandidates 对 RRF 合并后的前 TopN 个候选重排序
+// 重排序器未配置、超时或失败时保持 RRF 顺序，超时或失败时 fallback 为 true；
+// 重排序后的分数写入 FinalScore，其余候选分数不超过重排序的最低分
+func (s *SearchService) rerankCandidates(ctx context.Context, query string, candidates []searchCandidate) (reranked []searchCandidate, fallback bool) {
+	reranker := global.Reranker
+	if reranker == nil || len(candidates) < 2 {
+		return candidates, false
+	}
+
+	cfg := global.Config.Rerank
+	topN := cfg.TopN
+	if topN <= 0 {
+		topN = defaultRerankTopN
+	}
+	topN = min(topN, len(candidates))
+	timeout := defaultRerankTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+
+	documents := make([]string, topN)
+	for i := range documents {
+		documents[i] = rerankDocument(&candidates[i])
+	}
+
+	rerankCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	results, err := reranker.Rerank(rerankCtx, query, documents)
+	if err != nil {
+		global.Log.Warn("rerank failed, falling back to RRF order",
+			zap.String("reranker", reranker.Name()),
+			zap.Duration("elapsed", time.Since(start)),
+			zap.Error(err),
+		)
+		mcpLog(ctx, "warning", "search", map[string]interface{}{
+			"topic":    query,
+			"reranker": reranker.Name(),
+			"error":    err.Error(),
+		})
+		return candidates, true
+	}
+	if len(results) == 0 {
+		return candidates, false
+	}
+
+	// 按重排序结果重建前 TopN，未返回的候选保持原有相对顺序
+	reranked = make([]searchCandidate, 0, len(candidates))
+	placed := make([]bool, topN)
+	floor := 1.0
+	for _, r := range results {
+		if r.Index < 0 || r.Index >= topN || placed[r.Index] {
+			continue
+		}
+		placed[r.Index] = true
+		c := candidates[r.Index]
+		c.FinalScore = r.Score
+		floor = min(floor, r.Score)
+		reranked = append(reranked, c)
+	}
+	for i, c := range candidates {
+		if i < topN && placed[i] {
+			continue
+		}
+		c.FinalScore = min(c.FinalScore, floor)
+		reranked = append(reranked, c)
+	}
+
+	mcpLog(ctx, "debug", "search", map[string]interface{}{
+		"topic":    query,
+		"reranker": reranker.Name(),
+		"reranked": len(results),
+		"elapsed":  time.Since(start).String(),
+	})
+	return reranked, false
+}
+
+// rerankDocument 构建送入重排序的候选文本（标题、描述、正文）
+func rerankDocument(c *searchCandidate) string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{c.Chunk.Title, c.Chunk.Description, chunkBody(&c.Chunk)} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	text := strings.Join(parts, "\n\n")
+	if runes := []rune(text); len(runes) > rerankDocumentMaxRunes {
+		text = string(runes[:rerankDocumentMaxRunes])
+	}
+	return text
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// token 计数
+//
+// 入库切块（DocumentChunk.Tokens）与检索时的 token 预算装填共用同一编码与估算方式，
+// 两处的 token 数必须一致，否则按预算装填的结果会超出或浪费预算
+
+var (
+	tokenEncodingOnce sync.Once
+	tokenEncoding     *tiktoken.Tiktoken
+)
+
+// tokenEncoder cl100k_base 编码（GPT-4、text-embedding-3-small 使用的编码），加载失败返回 nil
+func tokenEncoder() *tiktoken.Tiktoken {
+	tokenEncodingOnce.Do(func() {
+		enc, err := tiktoken.GetEncoding("cl100k_base")
+		if err == nil {
+			tokenEncoding = enc
+		}
+	})
+	return tokenEncoding
+}
+
+// countTextTokens 计算文本 token 数（编码不可用时按 4 字符/token 估算）
+func countTextTokens(text string) int {
+	enc := tokenEncoder()
+	if enc == nil {
+		return len(text) / 4
+	}
+	return len(enc.Encode(text, nil, nil))
+}
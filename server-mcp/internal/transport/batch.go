@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"time"
+
+	"go-mcp-context/internal/model/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSON-RPC 批量请求支持
+//
+// 批量请求中的每个子调用使用独立的 BatchResponseWriter 收集响应，
+// 全部完成后由API层按传输协议统一写回（HTTP/Streamable 返回JSON数组，SSE 推送到会话流；stdio 写为一行JSON数组）
+
+const (
+	// MaxBatchSize 单个批量请求允许的最大调用数
+	MaxBatchSize = 50
+	// MaxBatchConcurrency 批量请求的最大并发数
+	MaxBatchConcurrency = 8
+)
+
+// 编译时检查接口实现
+var (
+	_ ResponseWriter = (*BatchResponseWriter)(nil)
+	_ SessionWriter  = (*BatchResponseWriter)(nil)
+)
+
+// BatchResponseWriter 批量请求子调用的响应收集器
+type BatchResponseWriter struct {
+	resp      *response.MCPResponse
+	sessionID string
+}
+
+// NewBatchResponseWriter 创建批量请求子调用的响应收集器
+func NewBatchResponseWriter() *BatchResponseWriter {
+	return &BatchResponseWriter{}
+}
+
+// WriteResponse 记录成功响应
+func (w *BatchResponseWriter) WriteResponse(resp *response.MCPResponse) error {
+	w.resp = resp
+	return nil
+}
+
+// WriteError 记录错误响应
+func (w *BatchResponseWriter) WriteError(err *response.MCPError, id interface{}) error {
+	w.resp = &response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   err,
+	}
+	return nil
+}
+
+// Close 关闭写入器
+// 收集器无需特殊清理
+func (w *BatchResponseWriter) Close() error {
+	return nil
+}
+
+// SetSessionID 记录 initialize 创建的会话ID，由API层写入响应头
+func (w *BatchResponseWriter) SetSessionID(sessionID string) {
+	w.sessionID = sessionID
+}
+
+// Response 获取收集到的响应（通知类请求为nil）
+func (w *BatchResponseWriter) Response() *response.MCPResponse {
+	return w.resp
+}
+
+// SessionID 获取子调用创建的会话ID
+func (w *BatchResponseWriter) SessionID() string {
+	return w.sessionID
+}
+
+// CallRecord 批量请求中单个子调用的执行记录
+// API层写入 gin.Context 的 "mcp_batch_calls"，调用日志中间件据此逐条记录
+type CallRecord struct {
+	// Method MCP方法名
+	Method string
+
+	// Body 子调用的原始请求体
+	Body map[string]interface{}
+
+	// GinCtx 子调用独立的上下文，携带 mcp_result_count、mcp_library_id 等统计信息
+	GinCtx *gin.Context
+
+	// Latency 子调用耗时
+	Latency time.Duration
+
+	// Error 子调用返回的错误（成功时为nil）
+	Error *response.MCPError
+}
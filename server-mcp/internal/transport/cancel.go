@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// 进行中请求的取消登记（notifications/cancelled）
+//
+// 以 "会话ID（stdio 无会话时为调用者ID）+ 请求ID" 标识请求（无会话的 HTTP 请求不登记），登记保存在处理该请求的实例内存中。
+// 多实例部署时同一会话的请求可能由任意实例处理：取消通知落在其他实例时经 Redis 转发，
+// 每个实例订阅所有取消信号，取消本实例登记的请求。
+
+var (
+	// inflightRequests 进行中的请求
+	inflightRequests sync.Map // key -> *inflightRequest
+
+	cancelRelayOnce sync.Once
+)
+
+// inflightRequest 进行中请求的取消函数（指针用于结束时只删除自己的登记）
+type inflightRequest struct {
+	cancel context.CancelFunc
+}
+
+// requestKey 生成请求标识（数字ID与字符串ID按文本比较）
+func requestKey(scope string, id interface{}) string {
+	return scope + "|" + fmt.Sprint(id)
+}
+
+// TrackRequest 登记进行中的请求，返回可被取消的 context 与结束函数
+// 请求处理完成后必须调用结束函数，释放登记并取消 context
+func TrackRequest(parent context.Context, scope string, id interface{}) (context.Context, func()) {
+	startCancelRelay()
+
+	ctx, cancel := context.WithCancel(parent)
+	key := requestKey(scope, id)
+	entry := &inflightRequest{cancel: cancel}
+	inflightRequests.Store(key, entry)
+
+	return ctx, func() {
+		inflightRequests.CompareAndDelete(key, entry)
+		cancel()
+	}
+}
+
+// CancelRequest 取消进行中的请求
+// 请求不在本实例时经 Redis 转发给处理该请求的实例，返回是否在本实例取消
+func CancelRequest(ctx context.Context, scope string, id interface{}) bool {
+	key := requestKey(scope, id)
+	if cancelLocal(key) {
+		return true
+	}
+
+	if store := session.GetStore(); store != nil {
+		if err := store.PublishCancel(ctx, key); err != nil {
+			global.Log.Warn("转发请求取消信号失败", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return false
+}
+
+// cancelLocal 取消本实例登记的请求
+func cancelLocal(key string) bool {
+	value, ok := inflightRequests.LoadAndDelete(key)
+	if !ok {
+		return false
+	}
+	value.(*inflightRequest).cancel()
+	return true
+}
+
+// startCancelRelay 订阅其他实例转发的取消信号（每个进程只启动一次，Redis 未初始化时跳过）
+func startCancelRelay() {
+	cancelRelayOnce.Do(func() {
+		store := session.GetStore()
+		if store == nil {
+			return
+		}
+
+		pubsub := store.SubscribeCancel(context.Background())
+		go func() {
+			for msg := range pubsub.Channel() {
+				cancelLocal(session.RequestKeyFromCancelMessage(msg))
+			}
+		}()
+	})
+}
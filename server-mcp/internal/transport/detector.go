@@ -11,11 +11,12 @@ import (
 func DetectTransport(c *gin.Context) TransportType {
 	// 1. 检测SessionID (SSE协议的特征)
 	// SSE协议需要先通过GET建立连接获取SessionID，后续POST请求会携带此ID
-	sessionID := c.GetHeader("MCP-Session-Id")
-	if sessionID != "" {
-		// 预留: 未来实现SSE协议时启用
-		// return TransportSSE
-		_ = sessionID // 避免未使用变量警告
+	if sessionID := SessionIDFromRequest(c); sessionID != "" {
+		if manager := GetConnectionManager(TransportSSE); manager != nil {
+			if _, err := manager.GetConnection(sessionID); err == nil {
+				return TransportSSE
+			}
+		}
 	}
 
 	// 2. 检测Accept头
@@ -36,8 +37,16 @@ func DetectTransport(c *gin.Context) TransportType {
 // GET请求通常用于建立SSE连接
 func DetectFromMethod(c *gin.Context) TransportType {
 	if c.Request.Method == "GET" {
-		// 预留: GET请求用于建立SSE连接
 		return TransportSSE
 	}
 	return DetectTransport(c)
 }
+
+// SessionIDFromRequest 从请求中提取SessionID
+// SSE协议通过endpoint事件下发的URL携带 ?sessionId=xxx，也兼容 MCP-Session-Id 头
+func SessionIDFromRequest(c *gin.Context) string {
+	if sessionID := c.Query("sessionId"); sessionID != "" {
+		return sessionID
+	}
+	return c.GetHeader("MCP-Session-Id")
+}
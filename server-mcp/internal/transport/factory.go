@@ -20,12 +20,11 @@ func CreateResponseWriter(c *gin.Context, transportType TransportType) ResponseW
 		return streamable.NewStreamableResponseWriter(c)
 
 	case TransportSSE:
-		// SSE协议: 通过预先建立的连接推送响应 (预留)
-		// 未来实现时的代码示例:
-		// sessionID := c.GetHeader("MCP-Session-Id")
-		// return sse.NewSSEResponseWriter(c, sessionID)
-
-		// 当前暂不支持，降级到HTTP
+		// SSE协议: 通过预先建立的连接推送响应
+		// 写入器由sse包在启动时注册，未注册时降级到HTTP
+		if factory := getWriterFactory(TransportSSE); factory != nil {
+			return factory(c)
+		}
 		return http.NewHTTPResponseWriter(c)
 
 	default:
@@ -17,7 +17,28 @@ type ResponseWriter interface {
 	Close() error
 }
 
-// ConnectionManager 连接管理器接口 (预留给SSE协议使用)
+// SessionWriter 支持会话的响应写入器接口 (Streamable HTTP协议使用)
+// initialize 成功后通过此接口将会话ID下发给客户端
+type SessionWriter interface {
+	// SetSessionID 设置响应携带的会话ID
+	SetSessionID(sessionID string)
+}
+
+// NotificationWriter 支持在响应之前推送通知的写入器接口
+// 用于 notifications/progress 等与当前请求关联的服务端通知
+type NotificationWriter interface {
+	// WriteNotification 写入通知（在最终响应之前）
+	WriteNotification(notification *response.MCPNotification) error
+}
+
+// RequestWriter 支持在响应之前向客户端发送服务端请求的写入器接口
+// 用于 elicitation/create 等需要客户端回复的请求，回复通过 AwaitResponse 接收
+type RequestWriter interface {
+	// WriteRequest 写入服务端请求（在最终响应之前）
+	WriteRequest(req *response.MCPServerRequest) error
+}
+
+// ConnectionManager 连接管理器接口 (SSE协议使用)
 // 用于管理多个客户端的SSE连接
 type ConnectionManager interface {
 	// Register 注册新连接
@@ -39,7 +60,7 @@ type ConnectionManager interface {
 	CleanupExpired() error
 }
 
-// Connection 连接接口 (预留给SSE协议使用)
+// Connection 连接接口 (SSE协议使用)
 // 表示单个客户端的SSE连接
 type Connection interface {
 	// SessionID 获取连接的SessionID
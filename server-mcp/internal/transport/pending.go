@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+// 服务端发起的请求（elicitation/create 等）
+//
+// 服务端在处理客户端请求的过程中向客户端发送请求，客户端的响应通过后续 POST
+// （stdio 为标准输入）送达。以 "会话ID（stdio 无会话时为调用者ID）+ 请求ID" 关联响应：
+//   - 等待者登记在本实例内存中，响应落在同一实例时直接交付
+//   - 多实例部署时等待者同时订阅该请求的 Redis 响应通道，响应落在其他实例时经 Redis 转发
+
+// pendingRequests 等待客户端响应的服务端请求
+var pendingRequests sync.Map // key -> chan *response.MCPResponse
+
+// NewServerRequestID 生成服务端请求ID（与客户端请求ID区分）
+func NewServerRequestID() string {
+	return "srv-" + uuid.Must(uuid.NewV4()).String()
+}
+
+// AwaitResponse 登记等待客户端响应的服务端请求，返回接收响应的 channel 与结束函数
+// 收到响应或放弃等待后必须调用结束函数释放登记
+func AwaitResponse(ctx context.Context, scope string, id interface{}) (<-chan *response.MCPResponse, func()) {
+	key := requestKey(scope, id)
+	ch := make(chan *response.MCPResponse, 1)
+	pendingRequests.Store(key, ch)
+
+	release := func() {
+		pendingRequests.CompareAndDelete(key, ch)
+	}
+
+	store := session.GetStore()
+	if store == nil {
+		return ch, release
+	}
+
+	// 订阅确认后再发送服务端请求，避免响应先于订阅到达
+	pubsub := store.SubscribeReply(ctx, key)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		global.Log.Warn("订阅客户端响应通道失败，仅接收本实例的响应", zap.String("key", key), zap.Error(err))
+		_ = pubsub.Close()
+		return ch, release
+	}
+	go func() {
+		for msg := range pubsub.Channel() {
+			var resp response.MCPResponse
+			if err := json.Unmarshal([]byte(msg.Payload), &resp); err != nil {
+				global.Log.Warn("解析转发的客户端响应失败", zap.String("key", key), zap.Error(err))
+				continue
+			}
+			deliverLocal(key, &resp)
+		}
+	}()
+
+	return ch, func() {
+		release()
+		_ = pubsub.Close()
+	}
+}
+
+// DeliverResponse 将客户端响应交给等待中的服务端请求
+// 等待者不在本实例时经 Redis 转发，没有任何实例在等待（已超时或请求不存在）时返回 false
+func DeliverResponse(ctx context.Context, scope string, resp *response.MCPResponse) bool {
+	key := requestKey(scope, resp.ID)
+	if deliverLocal(key, resp) {
+		return true
+	}
+
+	store := session.GetStore()
+	if store == nil {
+		return false
+	}
+	receivers, err := store.PublishReply(ctx, key, resp)
+	if err != nil {
+		global.Log.Warn("转发客户端响应失败", zap.String("key", key), zap.Error(err))
+		return false
+	}
+	return receivers > 0
+}
+
+// deliverLocal 将响应交给本实例的等待者
+func deliverLocal(key string, resp *response.MCPResponse) bool {
+	value, ok := pendingRequests.LoadAndDelete(key)
+	if !ok {
+		return false
+	}
+	value.(chan *response.MCPResponse) <- resp
+	return true
+}
+
+// ParseClientResponse 判断消息是否为客户端对服务端请求的响应（无 method，有 id 与 result/error）
+func ParseClientResponse(body []byte) (*response.MCPResponse, bool) {
+	var msg struct {
+		ID     interface{}        `json:"id"`
+		Method string             `json:"method"`
+		Result json.RawMessage    `json:"result"`
+		Error  *response.MCPError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil || msg.Method != "" || msg.ID == nil {
+		return nil, false
+	}
+	if msg.Result == nil && msg.Error == nil {
+		return nil, false
+	}
+
+	resp := &response.MCPResponse{JSONRPC: "2.0", ID: msg.ID, Error: msg.Error}
+	if msg.Result != nil && !bytes.Equal(msg.Result, []byte("null")) {
+		var result map[string]interface{}
+		if err := json.Unmarshal(msg.Result, &result); err != nil {
+			return nil, false
+		}
+		resp.Result = result
+	}
+	return resp, true
+}
@@ -0,0 +1,49 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"go-mcp-context/internal/transport/session"
+)
+
+// 服务端推送
+//
+// 向会话推送JSON-RPC通知，SSE 与 Streamable HTTP 共用：
+//   - 会话是本实例持有的 SSE 连接时，直接写入连接
+//   - 否则通过 Redis 发布到会话通道，由持有该会话 SSE 连接或 GET 通知流的实例写出
+
+// ErrNoSession 请求未关联会话，无法推送
+var ErrNoSession = errors.New("no session to push to")
+
+// Push 向指定会话推送消息
+func Push(ctx context.Context, sessionID string, msg interface{}) error {
+	if sessionID == "" {
+		return ErrNoSession
+	}
+
+	if manager := GetConnectionManager(TransportSSE); manager != nil {
+		if _, err := manager.GetConnection(sessionID); err == nil {
+			return manager.SendToSession(sessionID, msg)
+		}
+	}
+
+	store := session.GetStore()
+	if store == nil {
+		return ErrNoSession
+	}
+	return store.Publish(ctx, sessionID, msg)
+}
+
+// Broadcast 向所有会话推送消息（所有实例的 SSE 连接和 Streamable 通知流）
+func Broadcast(ctx context.Context, msg interface{}) error {
+	if store := session.GetStore(); store != nil {
+		return store.Broadcast(ctx, msg)
+	}
+
+	// 无 Redis 时只能推送给本实例的 SSE 连接
+	if manager := GetConnectionManager(TransportSSE); manager != nil {
+		return manager.BroadcastAll(msg)
+	}
+	return nil
+}
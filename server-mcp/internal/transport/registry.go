@@ -0,0 +1,44 @@
+package transport
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WriterFactory 响应写入器工厂
+// 有状态的传输协议（如SSE）通过注册工厂函数接入，避免transport包直接依赖具体实现
+type WriterFactory func(c *gin.Context) ResponseWriter
+
+var (
+	registryMu      sync.RWMutex
+	writerFactories = make(map[TransportType]WriterFactory)
+	connManagers    = make(map[TransportType]ConnectionManager)
+)
+
+// Register 注册传输协议的连接管理器和响应写入器工厂
+func Register(transportType TransportType, manager ConnectionManager, factory WriterFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if manager != nil {
+		connManagers[transportType] = manager
+	}
+	if factory != nil {
+		writerFactories[transportType] = factory
+	}
+}
+
+// GetConnectionManager 获取已注册的连接管理器（未注册返回nil）
+func GetConnectionManager(transportType TransportType) ConnectionManager {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return connManagers[transportType]
+}
+
+// getWriterFactory 获取已注册的响应写入器工厂（未注册返回nil）
+func getWriterFactory(transportType TransportType) WriterFactory {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return writerFactories[transportType]
+}
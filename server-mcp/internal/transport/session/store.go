@@ -0,0 +1,390 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-mcp-context/pkg/global"
+
+	"github.com/gofrs/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Streamable HTTP 会话存储
+//
+// 会话状态保存在 Redis 中，多实例部署时任意实例都可以校验和更新会话：
+//   - mcp:session:{id}          会话数据（JSON），空闲超过 SessionTTL 自动过期
+//   - mcp:session:attrs:{id}    会话属性（Hash），如日志级别，SSE 与 Streamable 会话通用
+//   - mcp:session:events:{id}   Pub/Sub 通道，服务端推送给客户端的消息
+//   - mcp:session:close:{id}    Pub/Sub 通道，会话终止信号
+//   - mcp:session:broadcast     Pub/Sub 通道，推送给所有会话的消息（如 list_changed）
+//   - mcp:session:stream:{id}   GET 通知流占用标记，同一会话同时只允许一个流
+//   - mcp:session:sse:{id}      SSE 连接在线标记，值为建立连接的用户（由持有连接的实例定期刷新），用于跨实例校验 SSE 会话
+//   - mcp:subs:library:{libId}  资源订阅（Set），成员为 "{sessionId}|{uri}"
+//   - mcp:subs:session:{id}     会话的资源订阅索引（Set），成员为 "{libId}|{uri}"，会话终止时据此清理订阅
+//   - mcp:session:reply:{key}   Pub/Sub 通道，客户端对服务端请求的响应，由等待该响应的实例订阅
+//   - mcp:session:cancel:{key}  Pub/Sub 通道，notifications/cancelled，由处理该请求的实例执行取消
+
+const (
+	// SessionTTL 会话空闲过期时间（每次请求刷新）
+	SessionTTL = 24 * time.Hour
+	// StreamLockTTL 通知流占用标记过期时间（由心跳刷新）
+	StreamLockTTL = 90 * time.Second
+
+	sessionKeyPrefix    = "mcp:session:"
+	attrsKeyPrefix      = "mcp:session:attrs:"
+	eventsChannelPrefix = "mcp:session:events:"
+	closeChannelPrefix  = "mcp:session:close:"
+	broadcastChannel    = "mcp:session:broadcast"
+	streamLockPrefix    = "mcp:session:stream:"
+	replyChannelPrefix  = "mcp:session:reply:"
+	ssePresencePrefix   = "mcp:session:sse:"
+	subsLibraryPrefix   = "mcp:subs:library:"
+	subsSessionPrefix   = "mcp:subs:session:"
+	cancelChannelPrefix = "mcp:session:cancel:"
+)
+
+// 会话属性名
+const (
+	// AttrLogLevel 客户端通过 logging/setLevel 设置的日志级别
+	AttrLogLevel = "log_level"
+	// AttrClient initialize 协商的协议版本、客户端能力与身份（JSON）
+	AttrClient = "client"
+)
+
+var (
+	// ErrSessionNotFound 会话不存在或已过期
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionForbidden 会话不属于当前用户
+	ErrSessionForbidden = errors.New("session belongs to another user")
+	// ErrStreamExists 会话已有活跃的通知流
+	ErrStreamExists = errors.New("session stream already open")
+)
+
+// Session 会话数据
+type Session struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// Store Redis 会话存储
+type Store struct {
+	client *redis.Client
+}
+
+var (
+	defaultStore *Store
+	once         sync.Once
+)
+
+// GetStore 获取全局会话存储（Redis 未初始化时返回 nil）
+func GetStore() *Store {
+	once.Do(func() {
+		if global.Redis != nil {
+			defaultStore = NewStore(global.Redis)
+		}
+	})
+	return defaultStore
+}
+
+// NewStore 创建会话存储
+func NewStore(client *redis.Client) *Store {
+	return &Store{client: client}
+}
+
+// Create 创建新会话
+func (s *Store) Create(ctx context.Context, userID string) (*Session, error) {
+	now := time.Now()
+	sess := &Session{
+		ID:           uuid.Must(uuid.NewV4()).String(),
+		UserID:       userID,
+		CreatedAt:    now,
+		LastActiveAt: now,
+	}
+	if err := s.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Get 获取会话
+func (s *Store) Get(ctx context.Context, sessionID string) (*Session, error) {
+	data, err := s.client.Get(ctx, sessionKeyPrefix+sessionID).Bytes()
+	if err == redis.Nil {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sess Session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return nil, err
+	}
+	return &sess, nil
+}
+
+// Validate 校验会话存在且属于指定用户，并刷新活跃时间
+func (s *Store) Validate(ctx context.Context, sessionID, userID string) (*Session, error) {
+	sess, err := s.Get(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sess.UserID != userID {
+		return nil, ErrSessionForbidden
+	}
+
+	sess.LastActiveAt = time.Now()
+	if err := s.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Save 保存会话（刷新过期时间）
+func (s *Store) Save(ctx context.Context, sess *Session) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, sessionKeyPrefix+sess.ID, data, SessionTTL).Err()
+}
+
+// Delete 终止会话，并通知持有通知流的实例关闭连接
+func (s *Store) Delete(ctx context.Context, sessionID string) error {
+	n, err := s.client.Del(ctx, sessionKeyPrefix+sessionID).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	s.client.Del(ctx, attrsKeyPrefix+sessionID)
+	if err := s.ClearResourceSubscriptions(ctx, sessionID); err != nil {
+		global.Log.Warn("清理会话资源订阅失败", zap.String("session_id", sessionID), zap.Error(err))
+	}
+	return s.client.Publish(ctx, closeChannelPrefix+sessionID, "close").Err()
+}
+
+// Alive 判断会话是否存活（Streamable HTTP 会话未过期，或 SSE 连接仍在线）
+func (s *Store) Alive(ctx context.Context, sessionID string) (bool, error) {
+	n, err := s.client.Exists(ctx, sessionKeyPrefix+sessionID, ssePresencePrefix+sessionID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// MarkSSEOnline 标记 SSE 连接在线并记录建立连接的用户（连接建立时及之后定期调用，StreamLockTTL 内未刷新视为断开）
+func (s *Store) MarkSSEOnline(ctx context.Context, sessionID, userID string) error {
+	return s.client.Set(ctx, ssePresencePrefix+sessionID, userID, StreamLockTTL).Err()
+}
+
+// SSEOwner 获取在线 SSE 会话的用户（连接可能由其他实例持有，不在线时返回 ErrSessionNotFound）
+func (s *Store) SSEOwner(ctx context.Context, sessionID string) (string, error) {
+	userID, err := s.client.Get(ctx, ssePresencePrefix+sessionID).Result()
+	if err == redis.Nil {
+		return "", ErrSessionNotFound
+	}
+	return userID, err
+}
+
+// MarkSSEOffline SSE 连接断开：移除在线标记、会话属性与资源订阅
+func (s *Store) MarkSSEOffline(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, ssePresencePrefix+sessionID, attrsKeyPrefix+sessionID).Err(); err != nil {
+		return err
+	}
+	return s.ClearResourceSubscriptions(ctx, sessionID)
+}
+
+// SetAttr 设置会话属性（与会话同样空闲 SessionTTL 后过期）
+func (s *Store) SetAttr(ctx context.Context, sessionID, field, value string) error {
+	key := attrsKeyPrefix + sessionID
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, field, value)
+	pipe.Expire(ctx, key, SessionTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetAttr 获取会话属性（未设置时返回空字符串）
+func (s *Store) GetAttr(ctx context.Context, sessionID, field string) (string, error) {
+	value, err := s.client.HGet(ctx, attrsKeyPrefix+sessionID, field).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return value, err
+}
+
+// Publish 向会话推送消息（由持有该会话通知流的实例写出）
+func (s *Store) Publish(ctx context.Context, sessionID string, msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, eventsChannelPrefix+sessionID, data).Err()
+}
+
+// Broadcast 向所有会话推送消息
+func (s *Store) Broadcast(ctx context.Context, msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, broadcastChannel, data).Err()
+}
+
+// Subscribe 订阅会话的消息通道、广播通道和终止信号
+func (s *Store) Subscribe(ctx context.Context, sessionID string) *redis.PubSub {
+	return s.client.Subscribe(ctx, eventsChannelPrefix+sessionID, broadcastChannel, closeChannelPrefix+sessionID)
+}
+
+// SubscribeAll 订阅所有会话的消息通道和广播通道（SSE 连接转发使用）
+func (s *Store) SubscribeAll(ctx context.Context) *redis.PubSub {
+	pubsub := s.client.PSubscribe(ctx, eventsChannelPrefix+"*")
+	if err := pubsub.Subscribe(ctx, broadcastChannel); err != nil {
+		global.Log.Warn("订阅会话广播通道失败", zap.Error(err))
+	}
+	return pubsub
+}
+
+// SessionIDFromMessage 从订阅消息中解析目标会话ID（广播消息返回空字符串）
+func SessionIDFromMessage(msg *redis.Message) string {
+	return strings.TrimPrefix(msg.Channel, eventsChannelPrefix)
+}
+
+// IsBroadcastMessage 判断订阅消息是否为广播
+func IsBroadcastMessage(msg *redis.Message) bool {
+	return msg.Channel == broadcastChannel
+}
+
+// IsCloseMessage 判断订阅消息是否为会话终止信号
+func IsCloseMessage(msg *redis.Message) bool {
+	return strings.HasPrefix(msg.Channel, closeChannelPrefix)
+}
+
+// AcquireStream 占用会话的通知流（同一会话同时只允许一个GET流）
+func (s *Store) AcquireStream(ctx context.Context, sessionID string) error {
+	ok, err := s.client.SetNX(ctx, streamLockPrefix+sessionID, 1, StreamLockTTL).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrStreamExists
+	}
+	return nil
+}
+
+// RefreshStream 刷新通知流占用标记
+func (s *Store) RefreshStream(ctx context.Context, sessionID string) error {
+	return s.client.Expire(ctx, streamLockPrefix+sessionID, StreamLockTTL).Err()
+}
+
+// ReleaseStream 释放通知流占用标记
+func (s *Store) ReleaseStream(ctx context.Context, sessionID string) error {
+	return s.client.Del(ctx, streamLockPrefix+sessionID).Err()
+}
+
+// ResourceSubscription 会话对资源的订阅
+type ResourceSubscription struct {
+	SessionID string
+	URI       string
+}
+
+// AddResourceSubscription 记录会话对库相关资源的订阅（与会话同样空闲 SessionTTL 后过期）
+func (s *Store) AddResourceSubscription(ctx context.Context, sessionID string, libraryID uint, uri string) error {
+	libraryKey := fmt.Sprintf("%s%d", subsLibraryPrefix, libraryID)
+	sessionKey := subsSessionPrefix + sessionID
+	pipe := s.client.TxPipeline()
+	pipe.SAdd(ctx, libraryKey, sessionID+"|"+uri)
+	pipe.Expire(ctx, libraryKey, SessionTTL)
+	pipe.SAdd(ctx, sessionKey, fmt.Sprintf("%d|%s", libraryID, uri))
+	pipe.Expire(ctx, sessionKey, SessionTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RemoveResourceSubscription 取消会话对资源的订阅
+func (s *Store) RemoveResourceSubscription(ctx context.Context, sessionID string, libraryID uint, uri string) error {
+	pipe := s.client.TxPipeline()
+	pipe.SRem(ctx, fmt.Sprintf("%s%d", subsLibraryPrefix, libraryID), sessionID+"|"+uri)
+	pipe.SRem(ctx, subsSessionPrefix+sessionID, fmt.Sprintf("%d|%s", libraryID, uri))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ResourceSubscriptions 获取订阅了库相关资源的会话
+func (s *Store) ResourceSubscriptions(ctx context.Context, libraryID uint) ([]ResourceSubscription, error) {
+	members, err := s.client.SMembers(ctx, fmt.Sprintf("%s%d", subsLibraryPrefix, libraryID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]ResourceSubscription, 0, len(members))
+	for _, member := range members {
+		sessionID, uri, ok := strings.Cut(member, "|")
+		if !ok {
+			continue
+		}
+		subs = append(subs, ResourceSubscription{SessionID: sessionID, URI: uri})
+	}
+	return subs, nil
+}
+
+// ClearResourceSubscriptions 移除会话的所有资源订阅（会话终止或连接断开时调用）
+func (s *Store) ClearResourceSubscriptions(ctx context.Context, sessionID string) error {
+	sessionKey := subsSessionPrefix + sessionID
+	members, err := s.client.SMembers(ctx, sessionKey).Result()
+	if err != nil {
+		return err
+	}
+
+	pipe := s.client.TxPipeline()
+	for _, member := range members {
+		libraryID, uri, ok := strings.Cut(member, "|")
+		if !ok {
+			continue
+		}
+		pipe.SRem(ctx, subsLibraryPrefix+libraryID, sessionID+"|"+uri)
+	}
+	pipe.Del(ctx, sessionKey)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// SubscribeReply 订阅客户端响应的转发通道（等待者所在实例订阅）
+func (s *Store) SubscribeReply(ctx context.Context, key string) *redis.PubSub {
+	return s.client.Subscribe(ctx, replyChannelPrefix+key)
+}
+
+// PublishReply 转发客户端对服务端请求的响应，返回收到消息的订阅者数量（0 表示没有实例在等待）
+func (s *Store) PublishReply(ctx context.Context, key string, msg interface{}) (int64, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return 0, err
+	}
+	return s.client.Publish(ctx, replyChannelPrefix+key, data).Result()
+}
+
+// PublishCancel 转发请求取消信号（由处理该请求的实例执行取消）
+func (s *Store) PublishCancel(ctx context.Context, key string) error {
+	return s.client.Publish(ctx, cancelChannelPrefix+key, "cancel").Err()
+}
+
+// SubscribeCancel 订阅所有请求的取消信号
+func (s *Store) SubscribeCancel(ctx context.Context) *redis.PubSub {
+	return s.client.PSubscribe(ctx, cancelChannelPrefix+"*")
+}
+
+// RequestKeyFromCancelMessage 从取消信号中解析请求标识
+func RequestKeyFromCancelMessage(msg *redis.Message) string {
+	return strings.TrimPrefix(msg.Channel, cancelChannelPrefix)
+}
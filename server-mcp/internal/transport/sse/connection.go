@@ -1,101 +1,193 @@
 package sse
 
-// SSE连接管理
-//
-// 当前状态: 预留，未实现
-//
-// SSEConnection 表示单个客户端的SSE连接
-//
-// 核心功能:
-// - 维护SessionID
-// - 维护HTTP ResponseWriter
-// - 提供Send方法推送消息
-// - 提供Close方法关闭连接
-// - 使用Channel进行消息传递
-// - 使用Goroutine监听消息并推送
-//
-// 示例代码框架:
-/*
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
+	"go-mcp-context/internal/transport"
+
 	"github.com/gin-gonic/gin"
 )
 
+// SSE连接管理
+//
+// SSEConnection 表示单个客户端的SSE连接 (MCP 2024-11-05 HTTP+SSE 传输)
+//
+// 工作流程:
+// 1. 客户端发送 GET /mcp 建立SSE连接
+// 2. 服务器生成SessionID，推送 endpoint 事件（POST地址，携带 ?sessionId=xxx）
+// 3. 客户端向 endpoint 发送 POST 请求，服务器返回 202 Accepted
+// 4. 服务器通过此连接推送 message 事件（JSON-RPC响应）
+//
+// 所有写入都在 Serve 所在的请求goroutine中完成，Send 只负责投递到消息通道
+
+const (
+	// messageBufferSize 消息通道缓冲区大小
+	messageBufferSize = 100
+	// sendTimeout 投递消息超时时间
+	sendTimeout = 5 * time.Second
+	// pingInterval 心跳间隔（SSE注释行，防止代理断开空闲连接）
+	pingInterval = 30 * time.Second
+	// idleTimeout 连接空闲超时时间，超过后视为过期
+	idleTimeout = 5 * time.Minute
+)
+
+var (
+	// ErrConnectionClosed 连接已关闭
+	ErrConnectionClosed = errors.New("connection closed")
+	// ErrSendTimeout 投递消息超时
+	ErrSendTimeout = errors.New("send timeout")
+)
+
+// 编译时检查接口实现
+var _ transport.Connection = (*SSEConnection)(nil)
+
+// SSEConnection SSE连接
 type SSEConnection struct {
-	SessionID    string
-	ClientIP     string
-	UserID       string
-	CreatedAt    time.Time
-	LastActiveAt time.Time
-	Writer       gin.ResponseWriter
-	MessageChan  chan interface{}
-	CloseChan    chan struct{}
-	closed       bool
+	sessionID    string
+	userID       string
+	clientIP     string
+	createdAt    time.Time
+	lastActiveAt time.Time
+
+	writer   gin.ResponseWriter
+	messages chan interface{}
+	done     chan struct{}
+
+	mu        sync.RWMutex
+	closeOnce sync.Once
 }
 
-func NewSSEConnection(sessionID string, c *gin.Context) *SSEConnection {
-	conn := &SSEConnection{
-		SessionID:    sessionID,
-		ClientIP:     c.ClientIP(),
-		CreatedAt:    time.Now(),
-		LastActiveAt: time.Now(),
-		Writer:       c.Writer,
-		MessageChan:  make(chan interface{}, 100),
-		CloseChan:    make(chan struct{}),
-		closed:       false,
+// NewSSEConnection 创建SSE连接
+func NewSSEConnection(c *gin.Context, sessionID, userID string) *SSEConnection {
+	now := time.Now()
+	return &SSEConnection{
+		sessionID:    sessionID,
+		userID:       userID,
+		clientIP:     c.ClientIP(),
+		createdAt:    now,
+		lastActiveAt: now,
+		writer:       c.Writer,
+		messages:     make(chan interface{}, messageBufferSize),
+		done:         make(chan struct{}),
 	}
+}
 
-	// 启动消息监听goroutine
-	go conn.listen()
+// SessionID 获取连接的SessionID
+func (c *SSEConnection) SessionID() string {
+	return c.sessionID
+}
 
-	return conn
+// UserID 获取建立连接的用户UUID
+func (c *SSEConnection) UserID() string {
+	return c.userID
 }
 
+// Send 投递消息到此连接（由 Serve 负责实际写出）
 func (c *SSEConnection) Send(data interface{}) error {
-	if c.closed {
-		return errors.New("connection closed")
+	select {
+	case <-c.done:
+		return ErrConnectionClosed
+	default:
 	}
 
 	select {
-	case c.MessageChan <- data:
-		c.LastActiveAt = time.Now()
+	case c.messages <- data:
+		c.touch()
 		return nil
-	case <-time.After(5 * time.Second):
-		return errors.New("send timeout")
+	case <-c.done:
+		return ErrConnectionClosed
+	case <-time.After(sendTimeout):
+		return ErrSendTimeout
 	}
 }
 
-func (c *SSEConnection) listen() {
+// Serve 推送 endpoint 事件并持续写出消息，直到客户端断开或连接被关闭
+// 必须在建立连接的请求goroutine中调用
+func (c *SSEConnection) Serve(ctx context.Context, endpoint string) error {
+	header := c.writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // 禁用 Nginx 缓冲
+	c.writer.WriteHeader(http.StatusOK)
+
+	if err := c.writeEvent("endpoint", []byte(endpoint)); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
-		case msg := <-c.MessageChan:
-			// 格式化为SSE格式并发送
-			data, _ := json.Marshal(msg)
-			c.Writer.Write([]byte("data: " + string(data) + "\n\n"))
-			if flusher, ok := c.Writer.(http.Flusher); ok {
-				flusher.Flush()
+		case msg := <-c.messages:
+			data, err := json.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			if err := c.writeEvent("message", data); err != nil {
+				return err
 			}
 
-		case <-c.CloseChan:
-			// 关闭连接
-			close(c.MessageChan)
-			return
+		case <-ticker.C:
+			if err := c.writeRaw(": ping\n\n"); err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return nil
+
+		case <-c.done:
+			return nil
 		}
 	}
 }
 
-func (c *SSEConnection) Close() error {
-	if !c.closed {
-		c.closed = true
-		close(c.CloseChan)
+// writeEvent 写出一个SSE事件
+func (c *SSEConnection) writeEvent(event string, data []byte) error {
+	return c.writeRaw(fmt.Sprintf("event: %s\ndata: %s\n\n", event, data))
+}
+
+// writeRaw 写出原始内容并立即刷新
+func (c *SSEConnection) writeRaw(s string) error {
+	if _, err := c.writer.Write([]byte(s)); err != nil {
+		return err
 	}
+	c.writer.Flush()
+	c.touch()
+	return nil
+}
+
+// touch 刷新最近活跃时间
+func (c *SSEConnection) touch() {
+	c.mu.Lock()
+	c.lastActiveAt = time.Now()
+	c.mu.Unlock()
+}
+
+// Close 关闭连接
+func (c *SSEConnection) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
 	return nil
 }
 
+// IsAlive 检查连接是否存活
 func (c *SSEConnection) IsAlive() bool {
-	return !c.closed && time.Since(c.LastActiveAt) < 5*time.Minute
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Since(c.lastActiveAt) < idleTimeout
 }
-*/
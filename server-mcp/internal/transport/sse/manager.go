@@ -1,65 +1,129 @@
 package sse
 
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
 // SSE连接管理器
 //
-// 当前状态: 预留，未实现
-//
 // SSEConnectionManager 管理所有客户端的SSE连接
 //
 // 核心功能:
-// - 注册新连接 (Register)
-// - 注销连接 (Unregister)
+// - 注册/注销连接 (Register/Unregister)
 // - 根据SessionID查找连接 (GetConnection)
 // - 向指定Session发送消息 (SendToSession)
 // - 广播消息给所有连接 (BroadcastAll)
-// - 清理过期连接 (CleanupExpired)
-// - 使用sync.Map或sync.RWMutex保证并发安全
-//
-// 示例代码框架:
-/*
-import (
-	"errors"
-	"sync"
-	"time"
+// - 定期清理过期连接 (CleanupExpired)
+// - 在会话存储中维护连接在线标记，断开时清理会话属性与资源订阅（Redis 未初始化时跳过）
+// - 校验会话归属，连接由其他实例持有时按在线标记校验 (ValidateSession)
+
+// cleanupInterval 过期连接清理间隔
+const cleanupInterval = 1 * time.Minute
+
+var (
+	// ErrSessionNotFound 会话不存在
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionForbidden 会话不属于当前用户
+	ErrSessionForbidden = errors.New("session belongs to another user")
 )
 
+// 编译时检查接口实现
+var _ transport.ConnectionManager = (*SSEConnectionManager)(nil)
+
+// SSEConnectionManager SSE连接管理器
 type SSEConnectionManager struct {
 	connections sync.Map // sessionID -> *SSEConnection
-	mu          sync.RWMutex
 }
 
-var globalManager *SSEConnectionManager
-var once sync.Once
+var (
+	globalManager *SSEConnectionManager
+	once          sync.Once
+)
 
+// GetGlobalSSEManager 获取全局SSE连接管理器（首次调用时启动定期清理）
 func GetGlobalSSEManager() *SSEConnectionManager {
 	once.Do(func() {
 		globalManager = &SSEConnectionManager{}
-		// 启动定期清理goroutine
 		go globalManager.cleanupLoop()
 	})
 	return globalManager
 }
 
-func (m *SSEConnectionManager) Register(conn *SSEConnection) error {
-	m.connections.Store(conn.SessionID, conn)
+// Register 注册新连接
+func (m *SSEConnectionManager) Register(conn transport.Connection) error {
+	m.connections.Store(conn.SessionID(), conn)
+	if store := session.GetStore(); store != nil {
+		if err := store.MarkSSEOnline(context.Background(), conn.SessionID(), connUserID(conn)); err != nil {
+			global.Log.Warn("标记SSE连接在线失败", zap.String("session_id", conn.SessionID()), zap.Error(err))
+		}
+	}
 	return nil
 }
 
+// Unregister 注销连接
 func (m *SSEConnectionManager) Unregister(sessionID string) error {
-	if conn, ok := m.connections.Load(sessionID); ok {
-		conn.(*SSEConnection).Close()
-		m.connections.Delete(sessionID)
+	conn, ok := m.connections.LoadAndDelete(sessionID)
+	if !ok {
+		return nil
+	}
+	conn.(transport.Connection).Close()
+	if store := session.GetStore(); store != nil {
+		if err := store.MarkSSEOffline(context.Background(), sessionID); err != nil {
+			global.Log.Warn("清理SSE会话状态失败", zap.String("session_id", sessionID), zap.Error(err))
+		}
 	}
 	return nil
 }
 
-func (m *SSEConnectionManager) GetConnection(sessionID string) (*SSEConnection, error) {
+// GetConnection 根据SessionID获取连接
+func (m *SSEConnectionManager) GetConnection(sessionID string) (transport.Connection, error) {
 	if conn, ok := m.connections.Load(sessionID); ok {
-		return conn.(*SSEConnection), nil
+		return conn.(transport.Connection), nil
+	}
+	return nil, ErrSessionNotFound
+}
+
+// ValidateSession 校验会话存在且属于指定用户
+// 多实例部署时 POST 可能落在未持有该连接的实例上，此时按会话存储中的在线标记校验，响应经 Redis 转发到持有连接的实例
+func (m *SSEConnectionManager) ValidateSession(ctx context.Context, sessionID, userID string) error {
+	owner := ""
+	if conn, err := m.GetConnection(sessionID); err == nil {
+		owner = connUserID(conn)
+	} else if store := session.GetStore(); store != nil {
+		if owner, err = store.SSEOwner(ctx, sessionID); err != nil {
+			if errors.Is(err, session.ErrSessionNotFound) {
+				return ErrSessionNotFound
+			}
+			return err
+		}
+	} else {
+		return err
+	}
+
+	if owner != userID {
+		return ErrSessionForbidden
 	}
-	return nil, errors.New("connection not found")
+	return nil
 }
 
+// connUserID 获取建立连接的用户UUID
+func connUserID(conn transport.Connection) string {
+	if sseConn, ok := conn.(*SSEConnection); ok {
+		return sseConn.UserID()
+	}
+	return ""
+}
+
+// SendToSession 向指定Session发送消息
 func (m *SSEConnectionManager) SendToSession(sessionID string, data interface{}) error {
 	conn, err := m.GetConnection(sessionID)
 	if err != nil {
@@ -68,32 +132,47 @@ func (m *SSEConnectionManager) SendToSession(sessionID string, data interface{})
 	return conn.Send(data)
 }
 
+// BroadcastAll 广播消息给所有连接
 func (m *SSEConnectionManager) BroadcastAll(data interface{}) error {
 	m.connections.Range(func(key, value interface{}) bool {
-		conn := value.(*SSEConnection)
-		conn.Send(data)
+		conn := value.(transport.Connection)
+		if err := conn.Send(data); err != nil {
+			global.Log.Warn("SSE广播消息失败",
+				zap.String("session_id", conn.SessionID()),
+				zap.Error(err),
+			)
+		}
 		return true
 	})
 	return nil
 }
 
+// CleanupExpired 清理过期连接，并刷新存活连接的在线标记
 func (m *SSEConnectionManager) CleanupExpired() error {
+	store := session.GetStore()
 	m.connections.Range(func(key, value interface{}) bool {
-		conn := value.(*SSEConnection)
+		conn := value.(transport.Connection)
 		if !conn.IsAlive() {
-			m.Unregister(conn.SessionID)
+			global.Log.Info("清理过期SSE连接", zap.String("session_id", conn.SessionID()))
+			m.Unregister(conn.SessionID())
+			return true
+		}
+		if store != nil {
+			if err := store.MarkSSEOnline(context.Background(), conn.SessionID(), connUserID(conn)); err != nil {
+				global.Log.Warn("刷新SSE连接在线标记失败", zap.String("session_id", conn.SessionID()), zap.Error(err))
+			}
 		}
 		return true
 	})
 	return nil
 }
 
+// cleanupLoop 定期清理过期连接
 func (m *SSEConnectionManager) cleanupLoop() {
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := time.NewTicker(cleanupInterval)
 	defer ticker.Stop()
 
 	for range ticker.C {
 		m.CleanupExpired()
 	}
 }
-*/
@@ -0,0 +1,50 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+
+	"go.uber.org/zap"
+)
+
+// Redis 消息转发
+//
+// 推送消息可能由任意实例发布（如其他实例上的文档导入），SSE 连接只存在于建立它的实例上。
+// 每个实例订阅所有会话通道和广播通道，把属于本实例连接的消息写入对应连接。
+
+var relayOnce sync.Once
+
+// startRelay 启动Redis消息转发（每个进程只启动一次，Redis 未初始化时跳过）
+func startRelay() {
+	relayOnce.Do(func() {
+		store := session.GetStore()
+		if store == nil {
+			return
+		}
+
+		pubsub := store.SubscribeAll(context.Background())
+		manager := GetGlobalSSEManager()
+		go func() {
+			for msg := range pubsub.Channel() {
+				payload := json.RawMessage(msg.Payload)
+
+				if session.IsBroadcastMessage(msg) {
+					manager.BroadcastAll(payload)
+					continue
+				}
+
+				sessionID := session.SessionIDFromMessage(msg)
+				if _, err := manager.GetConnection(sessionID); err != nil {
+					continue // 不是本实例的SSE连接
+				}
+				if err := manager.SendToSession(sessionID, payload); err != nil {
+					global.Log.Warn("转发SSE消息失败", zap.String("session_id", sessionID), zap.Error(err))
+				}
+			}
+		}()
+	})
+}
@@ -1,59 +1,51 @@
 package sse
 
-// SSE协议响应写入器实现
-//
-// 当前状态: 预留，未实现
-//
-// SSE协议工作流程:
-// 1. 客户端发送 GET /mcp 建立SSE连接
-// 2. 服务器生成SessionID并返回给客户端
-// 3. 客户端发送 POST /mcp (携带SessionID)
-// 4. 服务器返回202 Accepted
-// 5. 服务器通过SSE连接推送响应
-//
-// 实现时需要:
-// - SSEResponseWriter: 实现ResponseWriter接口
-// - 通过SSEConnectionManager查找对应的连接
-// - 将响应推送到SSE流
-//
-// 示例代码框架:
-/*
 import (
-	"encoding/json"
-	"github.com/gin-gonic/gin"
+	"net/http"
+
 	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport"
+
+	"github.com/gin-gonic/gin"
 )
 
+// SSE协议响应写入器实现
+//
+// POST请求本身只返回 202 Accepted，JSON-RPC响应通过对应会话的SSE流推送
+// （连接由其他实例持有时经 Redis 转发，见 transport.Push）
+
+// 编译时检查接口实现
+var (
+	_ transport.ResponseWriter     = (*SSEResponseWriter)(nil)
+	_ transport.NotificationWriter = (*SSEResponseWriter)(nil)
+	_ transport.RequestWriter      = (*SSEResponseWriter)(nil)
+)
+
+// SSEResponseWriter SSE响应写入器
 type SSEResponseWriter struct {
 	ctx       *gin.Context
 	sessionID string
-	manager   *SSEConnectionManager
+	accepted  bool
 }
 
-func NewSSEResponseWriter(c *gin.Context, sessionID string) *SSEResponseWriter {
+// NewSSEResponseWriter 创建SSE响应写入器（SessionID从请求中提取）
+func NewSSEResponseWriter(c *gin.Context) transport.ResponseWriter {
 	return &SSEResponseWriter{
 		ctx:       c,
-		sessionID: sessionID,
-		manager:   GetGlobalSSEManager(),
+		sessionID: transport.SessionIDFromRequest(c),
 	}
 }
 
+// WriteResponse 将响应推送到SSE流
 func (w *SSEResponseWriter) WriteResponse(resp *response.MCPResponse) error {
-	// 1. 查找对应的SSE连接
-	conn, err := w.manager.GetConnection(w.sessionID)
-	if err != nil {
-		return err
-	}
-
-	// 2. 将响应推送到SSE流
-	data, err := json.Marshal(resp)
-	if err != nil {
+	if err := w.push(resp); err != nil {
 		return err
 	}
-
-	return conn.Send(data)
+	w.accept()
+	return nil
 }
 
+// WriteError 将错误响应推送到SSE流
 func (w *SSEResponseWriter) WriteError(err *response.MCPError, id interface{}) error {
 	resp := &response.MCPResponse{
 		JSONRPC: "2.0",
@@ -63,7 +55,40 @@ func (w *SSEResponseWriter) WriteError(err *response.MCPError, id interface{}) e
 	return w.WriteResponse(resp)
 }
 
+// WriteNotification 将与当前请求关联的通知（如进度）推送到SSE流
+func (w *SSEResponseWriter) WriteNotification(notification *response.MCPNotification) error {
+	return w.push(notification)
+}
+
+// WriteRequest 将服务端请求（如 elicitation/create）推送到SSE流，客户端通过 POST 回复
+func (w *SSEResponseWriter) WriteRequest(req *response.MCPServerRequest) error {
+	return w.push(req)
+}
+
+// push 推送消息到会话的SSE流
+func (w *SSEResponseWriter) push(msg interface{}) error {
+	return transport.Push(w.ctx.Request.Context(), w.sessionID, msg)
+}
+
+// Close 关闭写入器
+// 通知类请求没有响应，同样需要返回 202 Accepted
 func (w *SSEResponseWriter) Close() error {
+	w.accept()
 	return nil
 }
-*/
+
+// accept 返回 202 Accepted（只写一次）
+func (w *SSEResponseWriter) accept() {
+	if w.accepted {
+		return
+	}
+	w.accepted = true
+	w.ctx.Status(http.StatusAccepted)
+	w.ctx.Writer.WriteHeaderNow()
+}
+
+// Register 将SSE协议注册到transport层，并启动跨实例消息转发
+func Register() {
+	transport.Register(transport.TransportSSE, GetGlobalSSEManager(), NewSSEResponseWriter)
+	startRelay()
+}
@@ -0,0 +1,26 @@
+package stdio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// ReadMessages 逐行读取 JSON-RPC 消息并交给 handle 处理，直到输入结束
+// 空行忽略；单行长度不受限制（文档内容可能很长）
+func ReadMessages(in io.Reader, handle func(line []byte)) error {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			handle(trimmed)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package stdio
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport"
+)
+
+// stdio协议响应写入器实现
+//
+// 每条 JSON-RPC 消息序列化为一行写入标准输出（消息内不含换行），
+// 多个请求并发处理时共享同一个写入器，写入按行加锁
+
+// 编译时检查接口实现
+var (
+	_ transport.ResponseWriter     = (*Writer)(nil)
+	_ transport.NotificationWriter = (*Writer)(nil)
+	_ transport.RequestWriter      = (*Writer)(nil)
+)
+
+// Writer stdio响应写入器（并发安全）
+type Writer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewWriter 创建stdio响应写入器
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{out: out}
+}
+
+// WriteResponse 写入成功响应
+func (w *Writer) WriteResponse(resp *response.MCPResponse) error {
+	return w.WriteMessage(resp)
+}
+
+// WriteError 写入错误响应
+func (w *Writer) WriteError(err *response.MCPError, id interface{}) error {
+	return w.WriteMessage(&response.MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   err,
+	})
+}
+
+// WriteNotification 写入通知（如进度）
+func (w *Writer) WriteNotification(notification *response.MCPNotification) error {
+	return w.WriteMessage(notification)
+}
+
+// WriteRequest 写入服务端请求（如 elicitation/create），客户端的响应从标准输入读取
+func (w *Writer) WriteRequest(req *response.MCPServerRequest) error {
+	return w.WriteMessage(req)
+}
+
+// WriteMessage 序列化消息并写为一行
+func (w *Writer) WriteMessage(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return w.WriteLine(data)
+}
+
+// WriteLine 写入一行已序列化的消息（代理模式直接转发远端消息）
+func (w *Writer) WriteLine(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.out.Write(data); err != nil {
+		return err
+	}
+	_, err := w.out.Write([]byte{'\n'})
+	return err
+}
+
+// Close 关闭写入器
+// 写入器在所有请求间共享，单个请求结束时无需处理
+func (w *Writer) Close() error {
+	return nil
+}
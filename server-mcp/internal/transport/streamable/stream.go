@@ -0,0 +1,69 @@
+package streamable
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-mcp-context/internal/transport/session"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pingInterval 通知流心跳间隔（同时刷新流占用标记）
+const pingInterval = 30 * time.Second
+
+// ServeSessionStream 打开会话的服务端通知流（GET /mcp）
+// 订阅会话在 Redis 中的消息通道，将推送的 JSON-RPC 消息写为SSE事件，
+// 直到客户端断开或会话被终止（DELETE /mcp）
+func ServeSessionStream(c *gin.Context, store *session.Store, sessionID string) error {
+	ctx := c.Request.Context()
+
+	if err := store.AcquireStream(ctx, sessionID); err != nil {
+		return err
+	}
+	defer store.ReleaseStream(context.Background(), sessionID)
+
+	pubsub := store.Subscribe(ctx, sessionID)
+	defer pubsub.Close()
+
+	// 等待订阅确认，确保之后发布的消息不会丢失
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 禁用 Nginx 缓冲
+	c.Header(HeaderSessionID, sessionID)
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok || session.IsCloseMessage(msg) {
+				return nil
+			}
+			if _, err := c.Writer.Write([]byte("data: " + msg.Payload + "\n\n")); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+
+		case <-ticker.C:
+			if _, err := c.Writer.Write([]byte(": ping\n\n")); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			store.RefreshStream(ctx, sessionID)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
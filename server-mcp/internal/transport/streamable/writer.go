@@ -2,13 +2,21 @@ package streamable
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/model/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// HeaderSessionID Streamable HTTP 会话ID请求/响应头
+const HeaderSessionID = "Mcp-Session-Id"
+
+// HeaderProtocolVersion 客户端在 initialize 之后的请求中声明的协议版本
+const HeaderProtocolVersion = "MCP-Protocol-Version"
+
 // StreamableResponseWriter Streamable HTTP响应写入器
 // 可以根据请求复杂度选择返回JSON或SSE流
 type StreamableResponseWriter struct {
@@ -16,6 +24,8 @@ type StreamableResponseWriter struct {
 	shouldStream bool // 是否使用流式响应
 	method       string
 	params       map[string]interface{}
+	written      bool // 是否已写入响应
+	headerSent   bool // 是否已发送SSE响应头
 }
 
 // NewStreamableResponseWriter 创建Streamable响应写入器
@@ -33,51 +43,25 @@ func (w *StreamableResponseWriter) SetRequestInfo(method string, params map[stri
 	w.shouldStream = shouldUseStreaming(method, params)
 }
 
+// SetSessionID 设置响应携带的会话ID（initialize 时下发）
+func (w *StreamableResponseWriter) SetSessionID(sessionID string) {
+	w.ctx.Header(HeaderSessionID, sessionID)
+}
+
 // shouldUseStreaming 判断是否应该使用流式响应
 // 根据请求的method和params判断是否需要流式推送
 //
-// 当前业务特点：所有查询都是一次性返回，速度快，数据量小
-// 因此暂时不使用流式响应，全部返回JSON
-//
-// 未来如果有以下需求时可以启用流式：
-// 1. 大数据量分批返回（1000+条记录）
-// 2. 长时间处理任务（10秒+）
-// 3. 需要实时进度反馈
+// 当前业务特点：大多数查询都是一次性返回，速度快，数据量小，直接返回JSON；
+// 请求携带 _meta.progressToken 时说明客户端希望接收进度，改用SSE流，
+// 在最终结果之前推送 notifications/progress
 func shouldUseStreaming(method string, params map[string]interface{}) bool {
-	// 当前全部返回JSON，不使用流式
-	return false
-
-	// 未来需要流式时，可以使用以下逻辑：
-	/*
-		switch method {
-		case "initialize", "tools/list", "resources/list", "resources/templates/list":
-			// 简单查询，直接返回JSON
-			return false
-
-		case "tools/call":
-			// 检查具体的工具调用
-			if toolName, ok := params["name"].(string); ok {
-				switch toolName {
-				case "search-libraries":
-					// 搜索可能需要较长时间，使用流式响应
-					return true
-				case "get-library-docs":
-					// 获取文档可能需要较长时间，使用流式响应
-					return true
-				}
-			}
-			return false
-
-		default:
-			// 其他方法默认不使用流式
-			return false
-		}
-	*/
+	return request.ProgressToken(params) != nil
 }
 
 // WriteResponse 写入成功响应
 // 根据shouldStream决定返回JSON还是SSE流
 func (w *StreamableResponseWriter) WriteResponse(resp *response.MCPResponse) error {
+	w.written = true
 	if w.shouldStream {
 		// 流式响应: 设置SSE响应头并推送数据
 		return w.writeSSEResponse(resp)
@@ -96,6 +80,7 @@ func (w *StreamableResponseWriter) WriteError(err *response.MCPError, id interfa
 		Error:   err,
 	}
 
+	w.written = true
 	if w.shouldStream {
 		return w.writeSSEResponse(resp)
 	}
@@ -104,56 +89,54 @@ func (w *StreamableResponseWriter) WriteError(err *response.MCPError, id interfa
 	return nil
 }
 
-// writeSSEResponse 写入SSE格式的响应
-// 将响应数据格式化为SSE格式并推送
-func (w *StreamableResponseWriter) writeSSEResponse(resp *response.MCPResponse) error {
-	// 设置SSE响应头
-	w.ctx.Header("Content-Type", "text/event-stream")
-	w.ctx.Header("Cache-Control", "no-cache")
-	w.ctx.Header("Connection", "keep-alive")
-	w.ctx.Header("Access-Control-Allow-Origin", "*")
-
-	// 可选: 发送进度消息
-	// w.sendProgress("处理中...")
-
-	// 发送最终结果
-	data, err := json.Marshal(resp)
-	if err != nil {
-		return err
-	}
-
-	// SSE格式: data: {json}\n\n
-	_, err = w.ctx.Writer.Write([]byte("data: " + string(data) + "\n\n"))
-	if err != nil {
-		return err
+// WriteNotification 在最终响应之前推送通知（仅流式响应时可用）
+func (w *StreamableResponseWriter) WriteNotification(notification *response.MCPNotification) error {
+	if !w.shouldStream {
+		return nil
 	}
+	return w.writeSSEEvent(notification)
+}
 
-	// 立即刷新，推送给客户端
-	if flusher, ok := w.ctx.Writer.(http.Flusher); ok {
-		flusher.Flush()
+// WriteRequest 在最终响应之前发送服务端请求（如 elicitation/create）
+// 尚未写入响应时改用SSE流，服务端请求与最终响应都通过该流推送，客户端通过新的 POST 回复
+func (w *StreamableResponseWriter) WriteRequest(req *response.MCPServerRequest) error {
+	if !w.shouldStream {
+		if w.written {
+			return errors.New("response already written")
+		}
+		w.shouldStream = true
 	}
+	return w.writeSSEEvent(req)
+}
 
-	return nil
+// writeSSEResponse 写入SSE格式的响应
+func (w *StreamableResponseWriter) writeSSEResponse(resp *response.MCPResponse) error {
+	return w.writeSSEEvent(resp)
 }
 
-// sendProgress 发送进度消息 (可选功能)
-// 用于在处理过程中向客户端推送进度信息
-func (w *StreamableResponseWriter) sendProgress(message string) error {
-	progressData := map[string]interface{}{
-		"type":    "progress",
-		"message": message,
+// writeSSEEvent 将消息格式化为SSE事件并推送
+// 第一次写入时设置SSE响应头
+func (w *StreamableResponseWriter) writeSSEEvent(message interface{}) error {
+	if !w.headerSent {
+		w.headerSent = true
+		w.ctx.Header("Content-Type", "text/event-stream")
+		w.ctx.Header("Cache-Control", "no-cache")
+		w.ctx.Header("Connection", "keep-alive")
+		w.ctx.Header("Access-Control-Allow-Origin", "*")
 	}
 
-	data, err := json.Marshal(progressData)
+	data, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
+	// SSE格式: data: {json}\n\n
 	_, err = w.ctx.Writer.Write([]byte("data: " + string(data) + "\n\n"))
 	if err != nil {
 		return err
 	}
 
+	// 立即刷新，推送给客户端
 	if flusher, ok := w.ctx.Writer.(http.Flusher); ok {
 		flusher.Flush()
 	}
@@ -162,6 +145,11 @@ func (w *StreamableResponseWriter) sendProgress(message string) error {
 }
 
 // Close 关闭写入器
+// 通知类请求没有响应，按规范返回 202 Accepted
 func (w *StreamableResponseWriter) Close() error {
+	if !w.written && !w.ctx.Writer.Written() {
+		w.ctx.Status(http.StatusAccepted)
+		w.ctx.Writer.WriteHeaderNow()
+	}
 	return nil
 }
@@ -1,6 +1,8 @@
 package transport
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,8 +16,11 @@ const (
 	// TransportStreamable Streamable HTTP协议 - 可以是JSON或SSE流
 	TransportStreamable TransportType = "streamable"
 
-	// TransportSSE SSE协议 - 需要预先建立连接 (预留)
+	// TransportSSE SSE协议 - 需要预先建立连接 (2024-11-05 HTTP+SSE)
 	TransportSSE TransportType = "sse"
+
+	// TransportStdio stdio协议 - 子进程标准输入输出，每行一条JSON-RPC消息
+	TransportStdio TransportType = "stdio"
 )
 
 // RequestContext MCP请求上下文
@@ -24,9 +29,12 @@ type RequestContext struct {
 	// Transport 传输协议类型
 	Transport TransportType
 
-	// SessionID 会话ID (仅SSE协议使用，当前为空)
+	// SessionID 会话ID (SSE协议的连接ID，或Streamable HTTP的Mcp-Session-Id)
 	SessionID string
 
+	// UserID 调用者UUID（由认证中间件解析）
+	UserID string
+
 	// Method MCP方法名
 	Method string
 
@@ -36,8 +44,65 @@ type RequestContext struct {
 	// ID 请求ID
 	ID interface{}
 
-	// GinCtx Gin上下文
+	// GinCtx Gin上下文（HTTP类协议；stdio 等非HTTP协议为 nil）
 	GinCtx *gin.Context
+
+	// Ctx 请求生命周期上下文（客户端断开或 notifications/cancelled 时取消）
+	Ctx context.Context
+
+	// ProtocolVersion 客户端声明的协议版本（HTTP 的 MCP-Protocol-Version 请求头，可为空）
+	ProtocolVersion string
+
+	// Client initialize 协商得到的客户端信息（未知时为 nil，由处理器从会话中加载）
+	Client *ClientInfo
+
+	// values 无 GinCtx 时保存的请求统计信息
+	values map[string]interface{}
+}
+
+// Set 记录请求统计信息（mcp_result_count、mcp_library_id 等）
+// 有 GinCtx 时写入 GinCtx 供调用日志中间件读取，否则保存在请求上下文中
+func (r *RequestContext) Set(key string, value interface{}) {
+	if r.GinCtx != nil {
+		r.GinCtx.Set(key, value)
+		return
+	}
+	if r.values == nil {
+		r.values = make(map[string]interface{})
+	}
+	r.values[key] = value
+}
+
+// Get 读取 Set 记录的请求统计信息
+func (r *RequestContext) Get(key string) (interface{}, bool) {
+	if r.GinCtx != nil {
+		return r.GinCtx.Get(key)
+	}
+	value, ok := r.values[key]
+	return value, ok
+}
+
+// Context 返回请求生命周期上下文（未设置时返回 context.Background()）
+func (r *RequestContext) Context() context.Context {
+	if r.Ctx != nil {
+		return r.Ctx
+	}
+	return context.Background()
+}
+
+// ClientInfo initialize 时协商的协议版本与客户端声明的能力、身份
+type ClientInfo struct {
+	// ProtocolVersion 协商后的协议版本
+	ProtocolVersion string `json:"protocolVersion"`
+
+	// Capabilities 客户端能力（roots、sampling、elicitation 等）
+	Capabilities map[string]interface{} `json:"capabilities,omitempty"`
+
+	// Name 客户端名称（clientInfo.name）
+	Name string `json:"name,omitempty"`
+
+	// Version 客户端版本（clientInfo.version）
+	Version string `json:"version,omitempty"`
 }
 
 // ResponseConfig 响应配置
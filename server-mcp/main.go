@@ -60,8 +60,10 @@ func main() {
 
 	global.Cache = initialize.InitCache() // 初始化通用缓存服务
 	global.Embedding = initialize.InitEmbedding()
-	initialize.InitStorage() // 初始化存储服务
-	initialize.InitLLM()     // 初始化 LLM 服务
+	initialize.InitStorage()       // 初始化存储服务
+	initialize.InitLLM()           // 初始化 LLM 服务
+	initialize.InitReranker()      // 初始化检索重排序服务（依赖 LLM）
+	initialize.InitEventHandlers() // 注册内部事件订阅（MCP 资源变更通知）
 
 	// 加载 SSO 公钥
 	if err := middleware.LoadSSOPublicKey(global.Config.SSO.PublicKeyPath); err != nil {
@@ -15,6 +15,7 @@ import (
 type LogEntry struct {
 	ActorID     string
 	FuncName    string
+	ToolName    string // tools/call 调用的工具名
 	LibraryID   *uint
 	Params      map[string]interface{} // 请求参数
 	ResultCount int
@@ -22,6 +23,10 @@ type LogEntry struct {
 	Status      string
 	ErrorMsg    string
 	CreatedAt   time.Time
+
+	ClientName      string // clientInfo.name
+	ClientVersion   string // clientInfo.version
+	ProtocolVersion string // 协商的协议版本
 }
 
 // mcpLogWriter 实现 bufferedwriter.Writer 接口
@@ -45,6 +50,7 @@ func (w *mcpLogWriter) WriteBatch(entries []*LogEntry) error {
 		logs = append(logs, dbmodel.MCPCallLog{
 			ActorID:     e.ActorID,
 			FuncName:    e.FuncName,
+			ToolName:    e.ToolName,
 			LibraryID:   e.LibraryID,
 			Params:      paramsJSON,
 			ResultCount: e.ResultCount,
@@ -52,6 +58,10 @@ func (w *mcpLogWriter) WriteBatch(entries []*LogEntry) error {
 			Status:      e.Status,
 			ErrorMsg:    e.ErrorMsg,
 			CreatedAt:   e.CreatedAt,
+
+			ClientName:      e.ClientName,
+			ClientVersion:   e.ClientVersion,
+			ProtocolVersion: e.ProtocolVersion,
 		})
 	}
 
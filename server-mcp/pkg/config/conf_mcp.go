@@ -0,0 +1,18 @@
+package config
+
+// MCP 协议配置
+type MCP struct {
+	PageSize     int    `json:"page_size" yaml:"page_size"`         // resources/list 等列表每页数量（默认 100）
+	CursorSecret string `json:"cursor_secret" yaml:"cursor_secret"` // 分页游标签名密钥（为空时使用 jwt.access_token_secret，两者都为空时使用进程内随机密钥）
+
+	DisabledTools []string `json:"disabled_tools" yaml:"disabled_tools"` // 关闭的工具名（不出现在 tools/list 中，调用返回未知工具）
+
+	OAuth MCPOAuth `json:"oauth" yaml:"oauth"` // OAuth 2.1 授权（与 MCP_API_KEY 并存）
+}
+
+// MCPOAuth MCP 端点作为 OAuth 受保护资源的配置
+type MCPOAuth struct {
+	Resource             string   `json:"resource" yaml:"resource"`                           // MCP 端点的规范 URL，如 https://mcp.example.com/mcp，同时是 access token 要求的 audience（为空时不启用 OAuth）
+	AuthorizationServers []string `json:"authorization_servers" yaml:"authorization_servers"` // 授权服务器地址（为空时使用 sso.service_url）
+	ScopesSupported      []string `json:"scopes_supported" yaml:"scopes_supported"`           // 支持的 scope（可选，写入受保护资源元数据）
+}
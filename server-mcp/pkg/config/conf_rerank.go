@@ -0,0 +1,12 @@
+package config
+
+// Rerank 检索重排序配置（混合检索 RRF 之后对前 TopN 个候选重排序）
+type Rerank struct {
+	Provider string `json:"provider" yaml:"provider"` // none（默认，保持 RRF 顺序）, llm（复用 llm 配置）, http（cross-encoder 服务）
+	BaseURL  string `json:"base_url" yaml:"base_url"` // http：rerank 接口完整地址，如 https://api.jina.ai/v1/rerank
+	APIKey   string `json:"api_key" yaml:"api_key"`   // http：API Key（可选）
+	Model    string `json:"model" yaml:"model"`       // http：模型名，如 jina-reranker-v2-base-multilingual
+	Format   string `json:"format" yaml:"format"`     // http：请求格式 cohere（默认，兼容 Jina）或 tei
+	TopN     int    `json:"top_n" yaml:"top_n"`       // 参与重排序的候选数（默认 20）
+	Timeout  string `json:"timeout" yaml:"timeout"`   // 超时时间，如 "3s"（默认 3s），超时或失败时回退到 RRF 顺序
+}
@@ -0,0 +1,15 @@
+package config
+
+// Search 混合检索配置（未设置的字段使用内置默认值，库级 search_weights 可覆盖）
+type Search struct {
+	VectorWeight *float64 `json:"vector_weight" yaml:"vector_weight"` // 向量检索 RRF 权重（默认 0.7）
+	BM25Weight   *float64 `json:"bm25_weight" yaml:"bm25_weight"`     // BM25 检索 RRF 权重（默认 0.3）
+	HotWeight    *float64 `json:"hot_weight" yaml:"hot_weight"`       // 热度权重（默认 0.2，设为 0 关闭热度加成）
+	RRFConstant  int      `json:"rrf_constant" yaml:"rrf_constant"`   // RRF 常量 k（默认 60）
+	VectorTopK   int      `json:"vector_top_k" yaml:"vector_top_k"`   // 向量检索候选数（默认 50）
+	BM25TopK     int      `json:"bm25_top_k" yaml:"bm25_top_k"`       // BM25 检索候选数（默认 50）
+
+	QueryExpansion   string `json:"query_expansion" yaml:"query_expansion"`     // LLM 查询扩展：off（默认）, expand（改写为多个子查询）, hyde（生成假设答案段落用于向量检索）, both
+	ExpansionQueries int    `json:"expansion_queries" yaml:"expansion_queries"` // expand 生成的子查询数（默认 3，最多 5）
+	ExpansionTimeout string `json:"expansion_timeout" yaml:"expansion_timeout"` // 查询扩展超时（默认 5s），超时或失败时按原 topic 检索
+}
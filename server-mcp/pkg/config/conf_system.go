@@ -2,9 +2,10 @@ package config
 
 // System 系统配置
 type System struct {
-	Host         string `json:"host" yaml:"host"`                   // 服务器地址
-	Port         int    `json:"port" yaml:"port"`                   // 服务器端口
-	Env          string `json:"env" yaml:"env"`                     // 环境：debug, release, test
-	RouterPrefix string `json:"router_prefix" yaml:"router_prefix"` // 路由前缀
-	StorageType  string `json:"storage_type" yaml:"storage_type"`   // 存储类型：local, qiniu
+	Host         string   `json:"host" yaml:"host"`                   // 服务器地址
+	Port         int      `json:"port" yaml:"port"`                   // 服务器端口
+	Env          string   `json:"env" yaml:"env"`                     // 环境：debug, release, test
+	RouterPrefix string   `json:"router_prefix" yaml:"router_prefix"` // 路由前缀
+	StorageType  string   `json:"storage_type" yaml:"storage_type"`   // 存储类型：local, qiniu
+	AdminUUIDs   []string `json:"admin_uuids" yaml:"admin_uuids"`     // 管理员用户 UUID（可管理全局 MCP 提示模板）
 }
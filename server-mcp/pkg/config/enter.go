@@ -7,6 +7,8 @@ type Config struct {
 	Redis     Redis     `json:"redis" yaml:"redis"`
 	Embedding Embedding `json:"embedding" yaml:"embedding"`
 	LLM       LLM       `json:"llm" yaml:"llm"`
+	Rerank    Rerank    `json:"rerank" yaml:"rerank"`
+	Search    Search    `json:"search" yaml:"search"`
 	Qiniu     Qiniu     `json:"qiniu" yaml:"qiniu"`
 	Chunker   Chunker   `json:"chunker" yaml:"chunker"`
 	Cache     Cache     `json:"cache" yaml:"cache"`
@@ -14,6 +16,7 @@ type Config struct {
 	SSO       SSO       `json:"sso" yaml:"sso"`
 	Zap       Zap       `json:"zap" yaml:"zap"`
 	GitHub    GitHub    `json:"github" yaml:"github"`
+	MCP       MCP       `json:"mcp" yaml:"mcp"`
 }
 
 // GitHub 配置
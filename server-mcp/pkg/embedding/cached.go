@@ -36,13 +36,18 @@ func NewCachedEmbeddingService(inner EmbeddingService, redis *redis.Client, logg
 
 // Embed 生成 embedding（带缓存）
 func (c *CachedEmbeddingService) Embed(text string) ([]float32, error) {
+	return c.EmbedWithContext(c.ctx, text)
+}
+
+// EmbedWithContext 生成 embedding（带缓存，ctx 取消时中止 Redis 与 API 调用）
+func (c *CachedEmbeddingService) EmbedWithContext(ctx context.Context, text string) ([]float32, error) {
 	// 1. 生成缓存 key（使用 text 的 MD5）
 	hash := md5.Sum([]byte(text))
 	cacheKey := embeddingCachePrefix + hex.EncodeToString(hash[:])
 
 	// 2. 尝试从 Redis 获取缓存
 	if c.redis != nil {
-		cached, err := c.redis.Get(c.ctx, cacheKey).Result()
+		cached, err := c.redis.Get(ctx, cacheKey).Result()
 		if err == nil && cached != "" {
 			// 缓存命中，解析 JSON
 			var vector []float32
@@ -59,12 +64,12 @@ func (c *CachedEmbeddingService) Embed(text string) ([]float32, error) {
 	if c.logger != nil {
 		c.logger.Debug("Embedding cache miss, calling API", zap.String("text_prefix", truncate(text, 50)))
 	}
-	vector, err := c.inner.Embed(text)
+	vector, err := c.inner.EmbedWithContext(ctx, text)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. 存入 Redis 缓存
+	// 4. 存入 Redis 缓存（请求已取消也保留结果，使用服务级 context）
 	if c.redis != nil {
 		if data, err := json.Marshal(vector); err == nil {
 			c.redis.Set(c.ctx, cacheKey, string(data), embeddingCacheTTL)
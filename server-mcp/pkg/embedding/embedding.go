@@ -1,6 +1,9 @@
 package embedding
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // ErrEmptyInput is returned when the input text is empty
 var ErrEmptyInput = errors.New("input text is empty")
@@ -13,6 +16,9 @@ type EmbeddingService interface {
 	// Embed generates embeddings for a single text
 	Embed(text string) ([]float32, error)
 
+	// EmbedWithContext generates embeddings for a single text, aborting when ctx is cancelled
+	EmbedWithContext(ctx context.Context, text string) ([]float32, error)
+
 	// EmbedBatch generates embeddings for multiple texts
 	EmbedBatch(texts []string) ([][]float32, error)
 
@@ -34,11 +34,16 @@ func NewOpenAIEmbedding(apiKey string, model string, dimension int) *OpenAIEmbed
 
 // Embed generates embedding for a single text
 func (e *OpenAIEmbedding) Embed(text string) ([]float32, error) {
+	return e.EmbedWithContext(context.Background(), text)
+}
+
+// EmbedWithContext generates embedding for a single text, aborting the API call when ctx is cancelled
+func (e *OpenAIEmbedding) EmbedWithContext(ctx context.Context, text string) ([]float32, error) {
 	if text == "" {
 		return nil, ErrEmptyInput
 	}
 
-	resp, err := e.client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+	resp, err := e.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
 		Input: []string{text},
 		Model: e.model,
 	})
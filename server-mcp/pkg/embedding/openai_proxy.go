@@ -43,11 +43,16 @@ func NewOpenAIProxyEmbedding(apiKey string, baseURL string, model string, dimens
 
 // Embed generates embedding for a single text
 func (e *OpenAIProxyEmbedding) Embed(text string) ([]float32, error) {
+	return e.EmbedWithContext(context.Background(), text)
+}
+
+// EmbedWithContext generates embedding for a single text, aborting the API call when ctx is cancelled
+func (e *OpenAIProxyEmbedding) EmbedWithContext(ctx context.Context, text string) ([]float32, error) {
 	if text == "" {
 		return nil, ErrEmptyInput
 	}
 
-	resp, err := e.client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+	resp, err := e.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
 		Input: []string{text},
 		Model: e.model,
 	})
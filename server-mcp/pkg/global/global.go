@@ -7,6 +7,7 @@ import (
 	"go-mcp-context/pkg/config"
 	"go-mcp-context/pkg/embedding"
 	"go-mcp-context/pkg/llm"
+	"go-mcp-context/pkg/rerank"
 	"go-mcp-context/pkg/storage"
 
 	"github.com/redis/go-redis/v9"
@@ -24,4 +25,5 @@ var (
 	Embedding embedding.EmbeddingService
 	Storage   storage.Storage // 文件存储服务
 	LLM       llm.LLMService  // LLM 服务
+	Reranker  rerank.Reranker // 检索重排序服务
 )
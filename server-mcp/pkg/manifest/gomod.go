@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseGoMod 解析 go.mod 的 require 指令（单行与块形式），跳过 // indirect 间接依赖
+func parseGoMod(content string) ([]Dependency, error) {
+	var deps []Dependency
+	inBlock := false
+	for i, line := range strings.Split(content, "\n") {
+		line, comment, _ := strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case !inBlock && fields[0] == "require":
+			if len(fields) == 2 && fields[1] == "(" {
+				inBlock = true
+				continue
+			}
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("go.mod line %d: malformed require", i+1)
+		}
+		if strings.TrimSpace(comment) == "indirect" {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      strings.Trim(fields[0], `"`),
+			Version:   fields[1],
+			Ecosystem: EcosystemGo,
+		})
+	}
+	return deps, nil
+}
@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// 项目依赖清单解析
+//
+// 支持 go.mod、package.json、requirements.txt、pom.xml，按文件名识别格式
+// （requirements 允许 requirements-dev.txt 等变体）；只解析依赖名与版本约束，不做版本求解
+
+// 生态
+const (
+	EcosystemGo    = "go"
+	EcosystemNPM   = "npm"
+	EcosystemPyPI  = "pypi"
+	EcosystemMaven = "maven"
+)
+
+// ErrUnsupportedManifest 不支持的清单文件
+var ErrUnsupportedManifest = errors.New("unsupported manifest file")
+
+// Dependency 清单中声明的依赖
+type Dependency struct {
+	Name      string // 依赖名（Go 模块路径、npm 包名、PyPI 包名、Maven groupId:artifactId）
+	Version   string // 版本或版本约束（原样保留，可能为空）
+	Ecosystem string
+	Dev       bool // 仅开发/测试使用（devDependencies、Maven test scope）
+}
+
+// Parse 按文件名解析清单内容
+func Parse(filename, content string) ([]Dependency, error) {
+	switch base := strings.ToLower(path.Base(strings.ReplaceAll(filename, "\\", "/"))); {
+	case base == "go.mod":
+		return parseGoMod(content)
+	case base == "package.json":
+		return parsePackageJSON(content)
+	case base == "pom.xml":
+		return parsePomXML(content)
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return parseRequirements(content)
+	}
+	return nil, ErrUnsupportedManifest
+}
@@ -0,0 +1,62 @@
+package manifest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pomPropertyPattern ${property} 引用
+var pomPropertyPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// parsePomXML 解析 pom.xml 的 dependencies（不含 dependencyManagement），版本中的 ${property} 按 properties 展开
+func parsePomXML(content string) ([]Dependency, error) {
+	type pomDependency struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+		Scope      string `xml:"scope"`
+	}
+	var pom struct {
+		Version    string `xml:"version"`
+		Properties struct {
+			Entries []struct {
+				XMLName xml.Name
+				Value   string `xml:",chardata"`
+			} `xml:",any"`
+		} `xml:"properties"`
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+	}
+	if err := xml.Unmarshal([]byte(content), &pom); err != nil {
+		return nil, fmt.Errorf("pom.xml: %w", err)
+	}
+
+	properties := map[string]string{"project.version": pom.Version}
+	for _, entry := range pom.Properties.Entries {
+		properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+	expand := func(value string) string {
+		return pomPropertyPattern.ReplaceAllStringFunc(strings.TrimSpace(value), func(ref string) string {
+			if v, ok := properties[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+	}
+
+	deps := make([]Dependency, 0, len(pom.Dependencies))
+	for _, d := range pom.Dependencies {
+		groupID, artifactID := expand(d.GroupID), expand(d.ArtifactID)
+		if artifactID == "" {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      groupID + ":" + artifactID,
+			Version:   expand(d.Version),
+			Ecosystem: EcosystemMaven,
+			Dev:       strings.TrimSpace(d.Scope) == "test",
+		})
+	}
+	return deps, nil
+}
@@ -0,0 +1,40 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// parsePackageJSON 解析 package.json 的 dependencies、peerDependencies、devDependencies
+func parsePackageJSON(content string) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies     map[string]string `json:"dependencies"`
+		PeerDependencies map[string]string `json:"peerDependencies"`
+		DevDependencies  map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return nil, fmt.Errorf("package.json: %w", err)
+	}
+
+	var deps []Dependency
+	seen := make(map[string]bool)
+	add := func(section map[string]string, dev bool) {
+		names := make([]string, 0, len(section))
+		for name := range section {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			deps = append(deps, Dependency{Name: name, Version: section[name], Ecosystem: EcosystemNPM, Dev: dev})
+		}
+	}
+	add(pkg.Dependencies, false)
+	add(pkg.PeerDependencies, false)
+	add(pkg.DevDependencies, true)
+	return deps, nil
+}
@@ -0,0 +1,42 @@
+package manifest
+
+import (
+	"strings"
+)
+
+// parseRequirements 解析 requirements.txt
+// 忽略注释、pip 选项（-r、-e、--index-url 等）、URL 依赖与环境标记（; python_version < "3.8"）
+func parseRequirements(content string) ([]Dependency, error) {
+	var deps []Dependency
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		line, _, _ = strings.Cut(line, ";")
+
+		// 名称到第一个版本操作符为止，extras（requests[security]）不属于名称
+		end := strings.IndexAny(line, "=<>!~ [")
+		if end < 0 {
+			end = len(line)
+		}
+		name := strings.TrimSpace(line[:end])
+		if name == "" {
+			continue
+		}
+		rest := line[end:]
+		if i := strings.IndexByte(rest, ']'); i >= 0 && strings.HasPrefix(strings.TrimSpace(rest), "[") {
+			rest = rest[i+1:]
+		}
+
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   strings.Join(strings.Fields(rest), ""),
+			Ecosystem: EcosystemPyPI,
+		})
+	}
+	return deps, nil
+}
@@ -0,0 +1,170 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// HTTP 重排序请求格式
+const (
+	FormatCohere = "cohere" // Cohere / Jina：{"query", "documents", "top_n"} -> {"results": [{"index", "relevance_score"}]}
+	FormatTEI    = "tei"    // Hugging Face Text Embeddings Inference：{"query", "texts"} -> [{"index", "score"}]
+)
+
+// HTTPReranker 通用 HTTP cross-encoder 重排序服务
+type HTTPReranker struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	model    string
+	format   string
+}
+
+// HTTPConfig HTTP 重排序配置
+type HTTPConfig struct {
+	Endpoint string // rerank 接口完整地址，如 https://api.jina.ai/v1/rerank
+	APIKey   string // 可选，以 Bearer token 发送
+	Model    string // 模型名（TEI 忽略）
+	Format   string // cohere（默认）或 tei
+}
+
+// NewHTTPReranker 创建 HTTP 重排序服务
+func NewHTTPReranker(cfg HTTPConfig) *HTTPReranker {
+	format := cfg.Format
+	if format == "" {
+		format = FormatCohere
+	}
+	return &HTTPReranker{
+		client:   &http.Client{},
+		endpoint: cfg.Endpoint,
+		apiKey:   cfg.APIKey,
+		model:    cfg.Model,
+		format:   format,
+	}
+}
+
+// cohereRerankRequest Cohere / Jina 请求体
+type cohereRerankRequest struct {
+	Model           string   `json:"model,omitempty"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            int      `json:"top_n"`
+	ReturnDocuments bool     `json:"return_documents"`
+}
+
+// cohereRerankResponse Cohere / Jina 响应体
+type cohereRerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// teiRerankRequest TEI 请求体
+type teiRerankRequest struct {
+	Query    string   `json:"query"`
+	Texts    []string `json:"texts"`
+	Truncate bool     `json:"truncate"`
+}
+
+// teiRerankResult TEI 响应体（数组元素）
+type teiRerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+}
+
+// Rerank 调用重排序服务为候选片段打分
+func (r *HTTPReranker) Rerank(ctx context.Context, query string, documents []string) ([]Result, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	var body interface{}
+	switch r.format {
+	case FormatTEI:
+		body = teiRerankRequest{Query: query, Texts: documents, Truncate: true}
+	case FormatCohere:
+		body = cohereRerankRequest{Model: r.model, Query: query, Documents: documents, TopN: len(documents)}
+	default:
+		return nil, fmt.Errorf("unsupported rerank format: %s", r.format)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read rerank response failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rerank request failed: status %d: %s", resp.StatusCode, truncateBody(data))
+	}
+
+	var results []Result
+	if r.format == FormatTEI {
+		var teiResults []teiRerankResult
+		if err := json.Unmarshal(data, &teiResults); err != nil {
+			return nil, fmt.Errorf("parse rerank response failed: %w", err)
+		}
+		for _, item := range teiResults {
+			results = append(results, Result{Index: item.Index, Score: item.Score})
+		}
+	} else {
+		var cohereResp cohereRerankResponse
+		if err := json.Unmarshal(data, &cohereResp); err != nil {
+			return nil, fmt.Errorf("parse rerank response failed: %w", err)
+		}
+		for _, item := range cohereResp.Results {
+			results = append(results, Result{Index: item.Index, Score: item.RelevanceScore})
+		}
+	}
+
+	// 过滤越界下标，按分数降序
+	valid := results[:0]
+	for _, res := range results {
+		if res.Index >= 0 && res.Index < len(documents) {
+			valid = append(valid, res)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].Score > valid[j].Score
+	})
+	return valid, nil
+}
+
+// Name 重排序器名称
+func (r *HTTPReranker) Name() string {
+	return "http:" + r.format
+}
+
+// truncateBody 截断错误响应体用于错误信息
+func truncateBody(data []byte) string {
+	if len(data) > 200 {
+		return string(data[:200]) + "..."
+	}
+	return string(data)
+}
+
+// 确保 HTTPReranker 实现了 Reranker 接口
+var _ Reranker = (*HTTPReranker)(nil)
@@ -0,0 +1,95 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-mcp-context/pkg/llm"
+)
+
+// LLMReranker 基于 LLM 的重排序：让模型为每个候选片段的相关性打分（0-10）
+type LLMReranker struct {
+	llm llm.LLMService
+}
+
+// NewLLMReranker 创建基于 LLM 的 Reranker
+func NewLLMReranker(service llm.LLMService) *LLMReranker {
+	return &LLMReranker{llm: service}
+}
+
+// llmRerankOutput LLM 返回的打分结果
+type llmRerankOutput struct {
+	Scores []struct {
+		Index int     `json:"index"`
+		Score float64 `json:"score"`
+	} `json:"scores"`
+}
+
+// Rerank 为候选片段打分
+func (r *LLMReranker) Rerank(ctx context.Context, query string, documents []string) ([]Result, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	reply, err := r.llm.Chat(ctx, buildLLMRerankPrompt(query, documents))
+	if err != nil {
+		return nil, fmt.Errorf("llm rerank failed: %w", err)
+	}
+
+	// 模型可能在 JSON 前后附带说明文字，只取最外层的 JSON 对象
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("llm rerank: no JSON in response")
+	}
+	var output llmRerankOutput
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &output); err != nil {
+		return nil, fmt.Errorf("llm rerank: parse response failed: %w", err)
+	}
+
+	seen := make(map[int]bool, len(output.Scores))
+	results := make([]Result, 0, len(output.Scores))
+	for _, s := range output.Scores {
+		if s.Index < 0 || s.Index >= len(documents) || seen[s.Index] {
+			continue
+		}
+		seen[s.Index] = true
+		results = append(results, Result{Index: s.Index, Score: min(max(s.Score/10, 0), 1)})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results, nil
+}
+
+// Name 重排序器名称
+func (r *LLMReranker) Name() string {
+	return "llm"
+}
+
+// buildLLMRerankPrompt 构建打分提示词
+func buildLLMRerankPrompt(query string, documents []string) string {
+	var b strings.Builder
+	b.WriteString("You are ranking documentation snippets for a developer's search query.\n\n")
+	fmt.Fprintf(&b, "## Query\n%s\n\n## Snippets\n", query)
+	for i, doc := range documents {
+		fmt.Fprintf(&b, "\n[%d]\n%s\n", i, doc)
+	}
+	b.WriteString(`
+## Task
+Score how well each snippet answers the query, from 0 (irrelevant) to 10 (directly answers it).
+Prefer snippets that are specific to the query over generic ones.
+
+## Return JSON:
+{"scores": [{"index": 0, "score": 7}]}
+
+## Rules
+- Include every snippet index exactly once
+- Return strict JSON only, no other content`)
+	return b.String()
+}
+
+// 确保 LLMReranker 实现了 Reranker 接口
+var _ Reranker = (*LLMReranker)(nil)
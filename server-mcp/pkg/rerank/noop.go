@@ -0,0 +1,26 @@
+package rerank
+
+import (
+	"context"
+)
+
+// NoopReranker 不做重排序，保持混合检索（RRF）的顺序
+type NoopReranker struct{}
+
+// NewNoopReranker 创建不做重排序的 Reranker
+func NewNoopReranker() *NoopReranker {
+	return &NoopReranker{}
+}
+
+// Rerank 返回空结果，不调整顺序
+func (r *NoopReranker) Rerank(ctx context.Context, query string, documents []string) ([]Result, error) {
+	return nil, nil
+}
+
+// Name 重排序器名称
+func (r *NoopReranker) Name() string {
+	return "none"
+}
+
+// 确保 NoopReranker 实现了 Reranker 接口
+var _ Reranker = (*NoopReranker)(nil)
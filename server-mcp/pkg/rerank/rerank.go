@@ -0,0 +1,20 @@
+package rerank
+
+import (
+	"context"
+)
+
+// Reranker 重排序接口：在混合检索之后按与查询的语义相关性为候选文档重新打分
+type Reranker interface {
+	// Rerank 为 documents 打分，返回结果按分数降序排列
+	// 结果可以只包含部分文档；未返回的文档保持原有相对顺序排在之后，返回空结果表示不调整顺序
+	Rerank(ctx context.Context, query string, documents []string) ([]Result, error)
+	// Name 重排序器名称（用于日志）
+	Name() string
+}
+
+// Result 单个文档的重排序结果
+type Result struct {
+	Index int     // documents 中的下标
+	Score float64 // 相关性分数 0-1
+}
@@ -0,0 +1,302 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 语义化版本解析与范围匹配（npm 风格的常用子集）
+//
+// 版本号允许 v 前缀与缺省的 minor/patch（如 v1、1.9、1.9.0-beta.1+build）；
+// 范围支持 ^1.9、~1.9.2、1.x、1.9.*、>=1.2 <2、1.x || 2.x 等写法
+
+// Semver 语义化版本
+type Semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string // 预发布标识（如 beta.1），为空表示正式版本
+}
+
+// ParseSemver 解析版本号（缺省部分补 0），无法解析时返回 false
+func ParseSemver(version string) (Semver, bool) {
+	p, ok := parsePartialSemver(version)
+	if !ok || p.wildcard {
+		return Semver{}, false
+	}
+	return p.Semver, true
+}
+
+// CompareSemver 比较版本先后（a<b 返回 -1，相等返回 0，a>b 返回 1）
+func CompareSemver(a, b Semver) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(a.Prerelease, b.Prerelease)
+}
+
+// MatchSemverRange 判断版本是否满足范围表达式；表达式无法解析时 valid 为 false
+// 预发布版本只有在范围本身带预发布标识时才参与匹配
+func MatchSemverRange(expr string, v Semver) (matched bool, valid bool) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return false, false
+	}
+	if v.Prerelease != "" && !strings.Contains(expr, "-") {
+		_, valid = parseSemverRange(expr)
+		return false, valid
+	}
+
+	alternatives, ok := parseSemverRange(expr)
+	if !ok {
+		return false, false
+	}
+	for _, comparators := range alternatives {
+		all := true
+		for _, c := range comparators {
+			if !c.match(v) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// semverComparator 单个比较条件
+type semverComparator struct {
+	op      string // >=、>、<=、<、=
+	version Semver
+}
+
+func (c semverComparator) match(v Semver) bool {
+	cmp := CompareSemver(v, c.version)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// partialSemver 范围中的版本（可能只写了部分，或带 x/* 通配）
+type partialSemver struct {
+	Semver
+	parts    int  // 明确写出的部分数（1-3）
+	wildcard bool // 是否包含通配符
+}
+
+// parseSemverRange 解析范围表达式为 "或" 关系的多组 "且" 条件
+func parseSemverRange(expr string) ([][]semverComparator, bool) {
+	var alternatives [][]semverComparator
+	for _, alt := range strings.Split(expr, "||") {
+		// 合并操作符与版本之间的空格（">= 1.2" -> ">=1.2"）
+		tokens := strings.Fields(alt)
+		var merged []string
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			if strings.Trim(token, "<>=^~") == "" && i+1 < len(tokens) {
+				token += tokens[i+1]
+				i++
+			}
+			merged = append(merged, token)
+		}
+		if len(merged) == 0 {
+			return nil, false
+		}
+
+		var comparators []semverComparator
+		for _, token := range merged {
+			parsed, ok := parseSemverComparators(token)
+			if !ok {
+				return nil, false
+			}
+			comparators = append(comparators, parsed...)
+		}
+		alternatives = append(alternatives, comparators)
+	}
+	return alternatives, true
+}
+
+// parseSemverComparators 将单个范围写法展开为比较条件
+func parseSemverComparators(token string) ([]semverComparator, bool) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(token, prefix) {
+			op = prefix
+			token = token[len(prefix):]
+			break
+		}
+	}
+
+	p, ok := parsePartialSemver(token)
+	if !ok {
+		return nil, false
+	}
+	if p.parts == 0 {
+		// *、x、x.x 等纯通配
+		return []semverComparator{{op: ">=", version: Semver{}}}, true
+	}
+	lower := p.Semver
+
+	switch op {
+	case "^":
+		// 不改变最左侧非零部分
+		var upper Semver
+		switch {
+		case lower.Major > 0 || p.parts == 1:
+			upper = Semver{Major: lower.Major + 1}
+		case lower.Minor > 0 || p.parts == 2:
+			upper = Semver{Minor: lower.Minor + 1}
+		default:
+			upper = Semver{Patch: lower.Patch + 1}
+		}
+		return rangeComparators(lower, upper), true
+
+	case "~":
+		// 允许 patch 变化（只写 major 时允许 minor 变化）
+		if p.parts == 1 {
+			return rangeComparators(lower, Semver{Major: lower.Major + 1}), true
+		}
+		return rangeComparators(lower, Semver{Major: lower.Major, Minor: lower.Minor + 1}), true
+
+	case ">=":
+		return []semverComparator{{op: ">=", version: lower}}, true
+
+	case ">":
+		if p.parts < 3 {
+			return []semverComparator{{op: ">=", version: p.bump()}}, true
+		}
+		return []semverComparator{{op: ">", version: lower}}, true
+
+	case "<":
+		return []semverComparator{{op: "<", version: lower}}, true
+
+	case "<=":
+		if p.parts < 3 {
+			return []semverComparator{{op: "<", version: p.bump()}}, true
+		}
+		return []semverComparator{{op: "<=", version: lower}}, true
+
+	default:
+		// 完整版本精确匹配；部分版本或通配（1、1.x、1.9.*）匹配该前缀下的所有版本
+		if p.parts == 3 {
+			return []semverComparator{{op: "=", version: lower}}, true
+		}
+		return rangeComparators(lower, p.bump()), true
+	}
+}
+
+// rangeComparators [lower, upper) 区间
+func rangeComparators(lower, upper Semver) []semverComparator {
+	return []semverComparator{{op: ">=", version: lower}, {op: "<", version: upper}}
+}
+
+// bump 部分版本的上界（1 -> 2.0.0，1.9 -> 1.10.0）
+func (p partialSemver) bump() Semver {
+	switch p.parts {
+	case 1:
+		return Semver{Major: p.Major + 1}
+	case 2:
+		return Semver{Major: p.Major, Minor: p.Minor + 1}
+	default:
+		return Semver{Major: p.Major, Minor: p.Minor, Patch: p.Patch + 1}
+	}
+}
+
+// parsePartialSemver 解析可能不完整或带通配符的版本号
+func parsePartialSemver(version string) (partialSemver, bool) {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i] // 构建元数据不参与比较
+	}
+
+	var p partialSemver
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		p.Prerelease = version[i+1:]
+		version = version[:i]
+		if p.Prerelease == "" {
+			return p, false
+		}
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return p, false
+	}
+	numbers := []*int{&p.Major, &p.Minor, &p.Patch}
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			p.wildcard = true
+			break // 通配之后的部分忽略
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return p, false
+		}
+		*numbers[i] = n
+		p.parts = i + 1
+	}
+	if p.parts == 0 && !p.wildcard {
+		return p, false
+	}
+	if p.wildcard && p.Prerelease != "" {
+		return p, false
+	}
+	return p, true
+}
+
+// comparePrerelease 比较预发布标识（正式版本高于预发布版本，数字标识按数值比较）
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1 // 数字标识低于字母标识
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
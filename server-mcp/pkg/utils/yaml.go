@@ -5,7 +5,11 @@ import (
 )
 
 // getConfigFile 根据环境变量获取配置文件路径
+// APP_CONFIG 指定配置文件路径时优先使用（如 stdio 子进程的工作目录不在项目根目录）
 func getConfigFile() string {
+	if path := os.Getenv("APP_CONFIG"); path != "" {
+		return path
+	}
 	env := os.Getenv("APP_ENV")
 	if env == "prod" || env == "production" {
 		return "configs/config.prod.yaml"
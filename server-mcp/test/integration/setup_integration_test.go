@@ -44,6 +44,9 @@ func TestMain(m *testing.M) {
 	// 8. 初始化 LLM 服务（使用真实的 OpenAI API）
 	initialize.InitLLM()
 
+	// 9. 初始化检索重排序服务（按配置，默认不重排序）
+	initialize.InitReranker()
+
 	fmt.Println("✅ Integration test environment initialized")
 
 	// 运行测试
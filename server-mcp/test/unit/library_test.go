@@ -2,6 +2,7 @@ package test_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"os"
@@ -346,6 +347,84 @@ func Test_Library_Update(t *testing.T) {
 			t.Error("Expected error when updating non-existent library, got nil")
 		}
 	})
+
+	t.Run("update library search weights", func(t *testing.T) {
+		lib, err := libService.Create(&request.LibraryCreate{Name: "weights-lib", Description: "api reference"})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		bm25Weight, hotWeight := 0.8, 0.0
+		updateReq := &request.LibraryUpdate{
+			Name:          lib.Name,
+			Description:   lib.Description,
+			SearchWeights: &dbmodel.SearchWeights{BM25Weight: &bm25Weight, HotWeight: &hotWeight},
+		}
+		if _, err := libService.Update(lib.ID, updateReq); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		info, err := libService.GetLibraryInfo(lib.ID)
+		if err != nil {
+			t.Fatalf("GetLibraryInfo() error = %v", err)
+		}
+		if info.SearchWeights == nil || info.SearchWeights.BM25Weight == nil || *info.SearchWeights.BM25Weight != 0.8 {
+			t.Fatalf("Expected bm25_weight 0.8, got %+v", info.SearchWeights)
+		}
+		if info.SearchWeights.HotWeight == nil || *info.SearchWeights.HotWeight != 0 {
+			t.Errorf("Expected hot_weight 0 to be stored, got %+v", info.SearchWeights.HotWeight)
+		}
+		if info.SearchWeights.VectorWeight != nil {
+			t.Errorf("Expected vector_weight to be unset, got %v", *info.SearchWeights.VectorWeight)
+		}
+
+		// 不传 search_weights 保持不变
+		if _, err := libService.Update(lib.ID, &request.LibraryUpdate{Name: lib.Name, Description: "renamed"}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if info, _ := libService.GetLibraryInfo(lib.ID); info.SearchWeights == nil {
+			t.Error("Expected search weights to be kept when omitted")
+		}
+
+		// 传空对象恢复全局配置
+		updateReq.SearchWeights = &dbmodel.SearchWeights{}
+		if _, err := libService.Update(lib.ID, updateReq); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if info, _ := libService.GetLibraryInfo(lib.ID); info.SearchWeights != nil {
+			t.Errorf("Expected search weights to be cleared, got %+v", info.SearchWeights)
+		}
+	})
+
+	t.Run("reject invalid search weights", func(t *testing.T) {
+		negative, topK := -1.0, 1000
+		for _, weights := range []*dbmodel.SearchWeights{
+			{VectorWeight: &negative},
+			{BM25TopK: &topK},
+		} {
+			_, err := libService.Update(1, &request.LibraryUpdate{Name: "any", SearchWeights: weights})
+			if !errors.Is(err, service.ErrInvalidParams) {
+				t.Errorf("Expected ErrInvalidParams for %+v, got %v", weights, err)
+			}
+		}
+	})
+}
+
+// Test_Library_SearchWeightsScan 测试 search_weights 扫描时重置接收者
+func Test_Library_SearchWeightsScan(t *testing.T) {
+	var w dbmodel.SearchWeights
+	if err := w.Scan([]byte(`{"vector_weight":0.5,"rrf_constant":10}`)); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if err := w.Scan([]byte(`{"bm25_weight":0.4}`)); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if w.VectorWeight != nil || w.RRFConstant != nil || w.BM25Weight == nil {
+		t.Errorf("Expected only bm25_weight after rescan, got %+v", w)
+	}
+	if err := w.Scan(nil); err != nil || !w.IsEmpty() {
+		t.Errorf("Expected empty weights after scanning NULL, got %+v, %v", w, err)
+	}
 }
 
 // Test_Library_SearchByName 测试按名称搜索库
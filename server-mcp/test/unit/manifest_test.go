@@ -0,0 +1,109 @@
+package test_test
+
+import (
+	"errors"
+	"testing"
+
+	"go-mcp-context/pkg/manifest"
+)
+
+// Test_Manifest_Parse 测试依赖清单解析
+func Test_Manifest_Parse(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+		want     []manifest.Dependency
+	}{
+		{
+			filename: "go.mod",
+			content: `module example.com/app
+
+go 1.22
+
+require github.com/gin-gonic/gin v1.9.1
+
+require (
+	gorm.io/gorm v1.25.5
+	github.com/redis/go-redis/v9 v9.3.0 // indirect
+)
+
+replace (
+	example.com/old => example.com/new v1.0.0
+)
+`,
+			want: []manifest.Dependency{
+				{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Ecosystem: manifest.EcosystemGo},
+				{Name: "gorm.io/gorm", Version: "v1.25.5", Ecosystem: manifest.EcosystemGo},
+			},
+		},
+		{
+			filename: "web/package.json",
+			content:  `{"dependencies": {"vue": "^3.4.0", "@vue/router": "~4.2.0"}, "devDependencies": {"vite": "5.x", "vue": "^3.4.0"}}`,
+			want: []manifest.Dependency{
+				{Name: "@vue/router", Version: "~4.2.0", Ecosystem: manifest.EcosystemNPM},
+				{Name: "vue", Version: "^3.4.0", Ecosystem: manifest.EcosystemNPM},
+				{Name: "vite", Version: "5.x", Ecosystem: manifest.EcosystemNPM, Dev: true},
+			},
+		},
+		{
+			filename: "requirements-dev.txt",
+			content: `# comment
+-r base.txt
+Django==4.2.1
+requests[security] >= 2.28, < 3 ; python_version >= "3.8"
+numpy
+git+https://github.com/org/pkg.git
+`,
+			want: []manifest.Dependency{
+				{Name: "Django", Version: "==4.2.1", Ecosystem: manifest.EcosystemPyPI},
+				{Name: "requests", Version: ">=2.28,<3", Ecosystem: manifest.EcosystemPyPI},
+				{Name: "numpy", Version: "", Ecosystem: manifest.EcosystemPyPI},
+			},
+		},
+		{
+			filename: "pom.xml",
+			content: `<project>
+  <version>1.0.0</version>
+  <properties><spring.version>5.3.10</spring.version></properties>
+  <dependencyManagement><dependencies><dependency><groupId>x</groupId><artifactId>managed</artifactId></dependency></dependencies></dependencyManagement>
+  <dependencies>
+    <dependency><groupId>org.springframework</groupId><artifactId>spring-core</artifactId><version>${spring.version}</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`,
+			want: []manifest.Dependency{
+				{Name: "org.springframework:spring-core", Version: "5.3.10", Ecosystem: manifest.EcosystemMaven},
+				{Name: "junit:junit", Version: "4.13.2", Ecosystem: manifest.EcosystemMaven, Dev: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := manifest.Parse(tt.filename, tt.content)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d dependencies, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Dependency %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+
+	t.Run("unsupported file", func(t *testing.T) {
+		if _, err := manifest.Parse("Cargo.toml", ""); !errors.Is(err, manifest.ErrUnsupportedManifest) {
+			t.Errorf("Expected ErrUnsupportedManifest, got %v", err)
+		}
+	})
+
+	t.Run("malformed package.json", func(t *testing.T) {
+		if _, err := manifest.Parse("package.json", "{"); err == nil {
+			t.Error("Expected error for malformed package.json")
+		}
+	})
+}
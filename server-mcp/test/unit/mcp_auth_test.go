@@ -0,0 +1,150 @@
+package test_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-mcp-context/internal/middleware"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/router"
+	"go-mcp-context/pkg/global"
+	"go-mcp-context/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Test_MCPAuth_OAuth 测试 MCP 端点的 OAuth 受保护资源元数据和 bearer access token 认证
+func Test_MCPAuth_OAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	oldKey, oldOAuth := middleware.SSOPublicKey, global.Config.MCP.OAuth
+	defer func() {
+		middleware.SSOPublicKey = oldKey
+		global.Config.MCP.OAuth = oldOAuth
+	}()
+	middleware.SSOPublicKey = &key.PublicKey
+	global.Config.MCP.OAuth.Resource = "https://mcp.example.com/mcp"
+	global.Config.MCP.OAuth.AuthorizationServers = []string{"https://sso.example.com"}
+
+	userUUID := uuid.Must(uuid.NewV4())
+	signToken := func(audience string, expiresAt time.Time) string {
+		claims := middleware.SSOClaims{
+			UserUUID:  userUUID,
+			AppID:     "ide-client",
+			TokenType: "access",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience:  jwt.ClaimStrings{audience},
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString() error = %v", err)
+		}
+		return token
+	}
+
+	r := gin.New()
+	router.RouterGroupApp.InitOAuthRouter(&r.RouterGroup)
+	r.POST("/mcp", middleware.MCPAuth(), func(c *gin.Context) {
+		c.String(http.StatusOK, utils.GetUUID(c).String())
+	})
+	call := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{}`))
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("protected resource metadata", func(t *testing.T) {
+		for _, path := range []string{"/.well-known/oauth-protected-resource", "/.well-known/oauth-protected-resource/mcp"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("GET %s: expected 200, got %d", path, w.Code)
+			}
+			var metadata response.OAuthProtectedResourceMetadata
+			if err := json.Unmarshal(w.Body.Bytes(), &metadata); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if metadata.Resource != "https://mcp.example.com/mcp" {
+				t.Errorf("Expected resource https://mcp.example.com/mcp, got %s", metadata.Resource)
+			}
+			if len(metadata.AuthorizationServers) != 1 || metadata.AuthorizationServers[0] != "https://sso.example.com" {
+				t.Errorf("Unexpected authorization servers: %v", metadata.AuthorizationServers)
+			}
+		}
+	})
+
+	t.Run("missing credentials get a challenge", func(t *testing.T) {
+		w := call("", "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected 401, got %d", w.Code)
+		}
+		challenge := w.Header().Get("WWW-Authenticate")
+		if !strings.Contains(challenge, `resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`) {
+			t.Errorf("Unexpected WWW-Authenticate: %s", challenge)
+		}
+	})
+
+	t.Run("valid access token", func(t *testing.T) {
+		w := call("Authorization", "Bearer "+signToken("https://mcp.example.com/mcp", time.Now().Add(time.Hour)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if w.Body.String() != userUUID.String() {
+			t.Errorf("Expected user_uuid %s, got %s", userUUID, w.Body.String())
+		}
+	})
+
+	t.Run("invalid access tokens", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			token       string
+			description string
+		}{
+			{"wrong audience", signToken("https://other.example.com/mcp", time.Now().Add(time.Hour)), "token audience mismatch"},
+			{"expired", signToken("https://mcp.example.com/mcp", time.Now().Add(-time.Hour)), "token expired"},
+			{"malformed", "not-a-jwt", "invalid token"},
+		}
+		for _, tt := range tests {
+			w := call("Authorization", "Bearer "+tt.token)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("%s: expected 401, got %d", tt.name, w.Code)
+				continue
+			}
+			challenge := w.Header().Get("WWW-Authenticate")
+			if !strings.Contains(challenge, `error="invalid_token"`) || !strings.Contains(challenge, tt.description) {
+				t.Errorf("%s: unexpected WWW-Authenticate: %s", tt.name, challenge)
+			}
+		}
+	})
+
+	t.Run("oauth disabled", func(t *testing.T) {
+		global.Config.MCP.OAuth.Resource = ""
+		defer func() { global.Config.MCP.OAuth.Resource = "https://mcp.example.com/mcp" }()
+
+		w := call("Authorization", "Bearer "+signToken("https://mcp.example.com/mcp", time.Now().Add(time.Hour)))
+		if w.Code == http.StatusOK {
+			t.Error("Expected access token to be rejected when OAuth is disabled")
+		}
+		if w.Header().Get("WWW-Authenticate") != "" {
+			t.Error("Expected no challenge when OAuth is disabled")
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package test_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-mcp-context/internal/api"
+	"go-mcp-context/internal/model/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newBatchTestRouter 创建仅挂载 MCP 入口的测试路由
+func newBatchTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/mcp", (&api.MCPApi{}).HandleRequest)
+	return r
+}
+
+// Test_MCPApi_BatchRequest 测试 JSON-RPC 批量请求
+func Test_MCPApi_BatchRequest(t *testing.T) {
+	r := newBatchTestRouter()
+
+	t.Run("mixed batch", func(t *testing.T) {
+		body := `[
+			{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}},
+			{"jsonrpc":"2.0","method":"notifications/initialized"},
+			{"jsonrpc":"2.0","id":2,"method":"unknown/method"},
+			{"foo":"bar"}
+		]`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var responses []response.MCPResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+			t.Fatalf("Expected JSON array, got %s", w.Body.String())
+		}
+
+		// 通知不返回响应
+		if len(responses) != 3 {
+			t.Fatalf("Expected 3 responses, got %d", len(responses))
+		}
+		if responses[0].Error != nil {
+			t.Errorf("Expected initialize success, got error %v", responses[0].Error)
+		}
+		if responses[1].Error == nil || responses[1].Error.Code != -32601 {
+			t.Errorf("Expected -32601 for unknown method, got %v", responses[1].Error)
+		}
+		if responses[2].Error == nil || responses[2].Error.Code != -32600 {
+			t.Errorf("Expected -32600 for invalid entry, got %v", responses[2].Error)
+		}
+	})
+
+	t.Run("notifications only", func(t *testing.T) {
+		body := `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+
+		if w.Code != http.StatusAccepted {
+			t.Errorf("Expected status 202, got %d", w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected empty body, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`[]`)))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package test_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test_Transport_CancelRequest 测试进行中请求的登记与取消
+func Test_Transport_CancelRequest(t *testing.T) {
+	t.Run("cancel by numeric id", func(t *testing.T) {
+		ctx, done := transport.TrackRequest(context.Background(), "cancel-scope", float64(7))
+		defer done()
+
+		if !transport.CancelRequest(context.Background(), "cancel-scope", 7) {
+			t.Fatal("Expected request to be found")
+		}
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("Expected context canceled, got %v", ctx.Err())
+		}
+		if transport.CancelRequest(context.Background(), "cancel-scope", 7) {
+			t.Error("Expected second cancel to find nothing")
+		}
+	})
+
+	t.Run("finished request is not cancellable", func(t *testing.T) {
+		_, done := transport.TrackRequest(context.Background(), "cancel-scope", "done-id")
+		done()
+
+		if transport.CancelRequest(context.Background(), "cancel-scope", "done-id") {
+			t.Error("Expected finished request to be unregistered")
+		}
+	})
+
+	t.Run("other scope is not affected", func(t *testing.T) {
+		ctx, done := transport.TrackRequest(context.Background(), "scope-a", "same-id")
+		defer done()
+
+		if transport.CancelRequest(context.Background(), "scope-b", "same-id") {
+			t.Error("Expected cancel in another scope to find nothing")
+		}
+		if ctx.Err() != nil {
+			t.Errorf("Expected context alive, got %v", ctx.Err())
+		}
+	})
+}
+
+// Test_MCPHandler_Cancelled 测试 notifications/cancelled 取消同一会话中的请求
+func Test_MCPHandler_Cancelled(t *testing.T) {
+	handler := service.NewMCPHandler()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	ctx, done := transport.TrackRequest(context.Background(), "cancel-session", "req-1")
+	defer done()
+
+	writer := newMockResponseWriter()
+	req := &transport.RequestContext{
+		Transport: transport.TransportStreamable,
+		SessionID: "cancel-session",
+		Method:    "notifications/cancelled",
+		Params: map[string]interface{}{
+			"requestId": "req-1",
+			"reason":    "user aborted",
+		},
+		GinCtx: c,
+	}
+
+	if err := handler.ProcessRequest(req, writer); err != nil {
+		t.Fatalf("ProcessRequest() error = %v", err)
+	}
+
+	if len(writer.responses) != 0 || len(writer.errors) != 0 {
+		t.Error("Expected no response for notification")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Expected tracked request to be cancelled, got %v", ctx.Err())
+	}
+}
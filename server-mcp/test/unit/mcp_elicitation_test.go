@@ -0,0 +1,114 @@
+package test_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
+)
+
+// elicitWriter 支持服务端请求的 mock 写入器，发出的请求通过 channel 交给模拟客户端
+type elicitWriter struct {
+	*mockResponseWriter
+	requests chan *response.MCPServerRequest
+}
+
+func (w *elicitWriter) WriteRequest(req *response.MCPServerRequest) error {
+	w.requests <- req
+	return nil
+}
+
+// Test_MCPElicitor 测试 elicitation/create 请求与客户端响应的关联
+func Test_MCPElicitor(t *testing.T) {
+	handler := service.NewMCPHandler()
+	client := &transport.ClientInfo{
+		ProtocolVersion: service.ProtocolVersion20250618,
+		Capabilities:    map[string]interface{}{"elicitation": map[string]interface{}{}},
+	}
+	newRequest := func() *transport.RequestContext {
+		return &transport.RequestContext{Transport: transport.TransportStdio, UserID: "elicitation-test-user", Method: "tools/call", ID: 1}
+	}
+
+	t.Run("unsupported clients get no elicitor", func(t *testing.T) {
+		writer := &elicitWriter{mockResponseWriter: newMockResponseWriter(), requests: make(chan *response.MCPServerRequest, 1)}
+		if service.NewMCPElicitor(newRequest(), nil, writer) != nil {
+			t.Error("Expected nil elicitor for unknown client")
+		}
+		if service.NewMCPElicitor(newRequest(), &transport.ClientInfo{ProtocolVersion: service.ProtocolVersion20250618}, writer) != nil {
+			t.Error("Expected nil elicitor without elicitation capability")
+		}
+		oldClient := &transport.ClientInfo{ProtocolVersion: service.ProtocolVersion20250326, Capabilities: client.Capabilities}
+		if service.NewMCPElicitor(newRequest(), oldClient, writer) != nil {
+			t.Error("Expected nil elicitor for protocol versions before 2025-06-18")
+		}
+		if service.NewMCPElicitor(newRequest(), client, newMockResponseWriter()) != nil {
+			t.Error("Expected nil elicitor for writers without server request support")
+		}
+	})
+
+	t.Run("choice is returned from client response", func(t *testing.T) {
+		writer := &elicitWriter{mockResponseWriter: newMockResponseWriter(), requests: make(chan *response.MCPServerRequest, 1)}
+		elicitor := service.NewMCPElicitor(newRequest(), client, writer)
+		if elicitor == nil {
+			t.Fatal("Expected elicitor")
+		}
+
+		go func() {
+			req := <-writer.requests
+			if req.Method != "elicitation/create" {
+				t.Errorf("Expected elicitation/create, got %s", req.Method)
+			}
+			handler.ProcessResponse(newRequest(), &response.MCPResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Result: map[string]interface{}{
+					"action":  "accept",
+					"content": map[string]interface{}{"version": "v2"},
+				},
+			})
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		choice, ok, err := elicitor.ElicitChoice(ctx, "Pick a version", "version", "Version", []string{"v1", "v2"}, nil)
+		if err != nil || !ok || choice != "v2" {
+			t.Errorf("Expected choice v2, got %q ok=%v err=%v", choice, ok, err)
+		}
+	})
+
+	t.Run("declined and out-of-list choices", func(t *testing.T) {
+		for _, result := range []map[string]interface{}{
+			{"action": "decline"},
+			{"action": "accept", "content": map[string]interface{}{"version": "v9"}},
+		} {
+			writer := &elicitWriter{mockResponseWriter: newMockResponseWriter(), requests: make(chan *response.MCPServerRequest, 1)}
+			elicitor := service.NewMCPElicitor(newRequest(), client, writer)
+			go func() {
+				req := <-writer.requests
+				handler.ProcessResponse(newRequest(), &response.MCPResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+			}()
+			if _, ok, err := elicitor.ElicitChoice(context.Background(), "Pick a version", "version", "Version", []string{"v1", "v2"}, nil); ok || err != nil {
+				t.Errorf("Expected no selection for %v, got ok=%v err=%v", result, ok, err)
+			}
+		}
+	})
+
+	t.Run("response without pending request", func(t *testing.T) {
+		if handler.ProcessResponse(newRequest(), &response.MCPResponse{JSONRPC: "2.0", ID: "srv-unknown", Result: map[string]interface{}{}}) {
+			t.Error("Expected unmatched response not to be delivered")
+		}
+	})
+
+	t.Run("parse client response", func(t *testing.T) {
+		resp, ok := transport.ParseClientResponse([]byte(`{"jsonrpc":"2.0","id":"srv-1","result":{"action":"cancel"}}`))
+		if !ok || resp.ID != "srv-1" {
+			t.Fatalf("Expected client response, got %v ok=%v", resp, ok)
+		}
+		if _, ok := transport.ParseClientResponse([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)); ok {
+			t.Error("Expected request not to be parsed as a response")
+		}
+	})
+}
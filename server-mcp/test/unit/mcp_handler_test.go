@@ -5,6 +5,7 @@ import (
 	"mime/multipart"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -97,6 +98,96 @@ func Test_MCPHandler_Initialize(t *testing.T) {
 	})
 }
 
+// Test_MCPHandler_ProtocolNegotiation 测试协议版本协商与按版本启用特性
+func Test_MCPHandler_ProtocolNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := service.NewMCPHandler()
+
+	initialize := func(version string) (*mockResponseWriter, *transport.RequestContext) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		writer := newMockResponseWriter()
+		req := &transport.RequestContext{
+			Transport: transport.TransportHTTP,
+			Method:    "initialize",
+			Params: map[string]interface{}{
+				"protocolVersion": version,
+				"capabilities":    map[string]interface{}{"roots": map[string]interface{}{}},
+				"clientInfo":      map[string]interface{}{"name": "test-client", "version": "0.1.0"},
+			},
+			ID:     1,
+			GinCtx: c,
+		}
+		if err := handler.ProcessRequest(req, writer); err != nil {
+			t.Fatalf("ProcessRequest() error = %v", err)
+		}
+		return writer, req
+	}
+
+	tests := []struct {
+		requested string
+		expected  string
+	}{
+		{"2025-11-25", "2025-11-25"},
+		{"2025-06-18", "2025-06-18"},
+		{"2024-11-05", "2024-11-05"},
+		{"2099-01-01", service.LatestProtocolVersion},
+	}
+	for _, tt := range tests {
+		t.Run("negotiate "+tt.requested, func(t *testing.T) {
+			writer, req := initialize(tt.requested)
+			if len(writer.responses) != 1 {
+				t.Fatalf("Expected 1 response, got %d (errors: %d)", len(writer.responses), len(writer.errors))
+			}
+			result := writer.responses[0].Result.(map[string]interface{})
+			if result["protocolVersion"] != tt.expected {
+				t.Errorf("Expected protocolVersion %s, got %v", tt.expected, result["protocolVersion"])
+			}
+			if req.Client == nil || req.Client.Name != "test-client" || req.Client.ProtocolVersion != tt.expected {
+				t.Errorf("Expected client info to be recorded, got %+v", req.Client)
+			}
+			if _, ok := req.Get("mcp_client"); !ok {
+				t.Error("Expected mcp_client to be set for call logs")
+			}
+		})
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		for _, version := range []string{"2023-01-01", "latest"} {
+			writer, _ := initialize(version)
+			if len(writer.errors) != 1 || writer.errors[0].Code != -32602 {
+				t.Errorf("Expected -32602 error for %s, got %+v", version, writer.errors)
+			}
+		}
+	})
+
+	t.Run("older version omits completions and outputSchema", func(t *testing.T) {
+		writer, _ := initialize("2024-11-05")
+		capabilities := writer.responses[0].Result.(map[string]interface{})["capabilities"].(map[string]interface{})
+		if _, ok := capabilities["completions"]; ok {
+			t.Error("Expected no completions capability for 2024-11-05")
+		}
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		listWriter := newMockResponseWriter()
+		req := &transport.RequestContext{
+			Transport: transport.TransportHTTP,
+			Method:    "tools/list",
+			ID:        2,
+			GinCtx:    c,
+			Client:    &transport.ClientInfo{ProtocolVersion: "2024-11-05"},
+		}
+		if err := handler.ProcessRequest(req, listWriter); err != nil {
+			t.Fatalf("ProcessRequest() error = %v", err)
+		}
+		tools := listWriter.responses[0].Result.(map[string]interface{})["tools"].([]map[string]interface{})
+		for _, tool := range tools {
+			if _, ok := tool["outputSchema"]; ok {
+				t.Errorf("Expected no outputSchema for %v", tool["name"])
+			}
+		}
+	})
+}
+
 // Test_MCPHandler_Initialized 测试 initialized 通知
 func Test_MCPHandler_Initialized(t *testing.T) {
 	handler := service.NewMCPHandler()
@@ -171,8 +262,8 @@ func Test_MCPHandler_ToolsList(t *testing.T) {
 			t.Fatalf("Expected tools to be []interface{} or []map[string]interface{}, got %T", toolsRaw)
 		}
 
-		if toolsCount != 2 {
-			t.Errorf("Expected 2 tools, got %d", toolsCount)
+		if toolsCount != 3 {
+			t.Errorf("Expected 3 tools, got %d", toolsCount)
 		}
 	})
 }
@@ -219,6 +310,22 @@ func Test_MCPHandler_ToolsCall(t *testing.T) {
 		if len(writer.responses) != 1 {
 			t.Fatalf("Expected 1 response, got %d", len(writer.responses))
 		}
+
+		// structuredContent 与 Markdown 文本描述同一份结果
+		result := writer.responses[0].Result.(map[string]interface{})
+		structured, ok := result["structuredContent"].(*response.MCPSearchLibrariesResult)
+		if !ok {
+			t.Fatalf("Expected structuredContent to be *MCPSearchLibrariesResult, got %T", result["structuredContent"])
+		}
+		text := result["content"].([]map[string]interface{})[0]["text"].(string)
+		if strings.HasPrefix(text, "{") {
+			t.Errorf("Expected markdown text, got JSON: %s", text)
+		}
+		for _, lib := range structured.Libraries {
+			if !strings.Contains(text, fmt.Sprintf("### %s (libraryId: %d)", lib.Name, lib.LibraryID)) {
+				t.Errorf("Expected markdown to describe library %s", lib.Name)
+			}
+		}
 	})
 
 	t.Run("call get-library-docs tool", func(t *testing.T) {
@@ -480,6 +587,146 @@ func Test_MCPHandler_UnknownMethod(t *testing.T) {
 	})
 }
 
+// Test_MCPHandler_LoggingSetLevel 测试 logging/setLevel 方法
+func Test_MCPHandler_LoggingSetLevel(t *testing.T) {
+	handler := service.NewMCPHandler()
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		wantCode int
+	}{
+		{"invalid level", map[string]interface{}{"level": "verbose"}, -32602},
+		{"missing level", map[string]interface{}{}, -32602},
+		{"stateless request", map[string]interface{}{"level": "debug"}, -32600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			writer := newMockResponseWriter()
+
+			req := &transport.RequestContext{
+				Transport: transport.TransportHTTP,
+				Method:    "logging/setLevel",
+				Params:    tt.params,
+				ID:        1,
+				GinCtx:    c,
+			}
+
+			if err := handler.ProcessRequest(req, writer); err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+
+			if len(writer.errors) != 1 {
+				t.Fatalf("Expected 1 error, got %d", len(writer.errors))
+			}
+			if writer.errors[0].Code != tt.wantCode {
+				t.Errorf("Expected error code %d, got %d", tt.wantCode, writer.errors[0].Code)
+			}
+		})
+	}
+}
+
+// Test_MCPHandler_ResourcesSubscribe 测试 resources/subscribe 与 resources/unsubscribe
+func Test_MCPHandler_ResourcesSubscribe(t *testing.T) {
+	handler := service.NewMCPHandler()
+
+	tests := []struct {
+		name     string
+		method   string
+		params   map[string]interface{}
+		wantCode int
+	}{
+		{"missing uri", "resources/subscribe", map[string]interface{}{}, -32602},
+		{"unsupported uri", "resources/subscribe", map[string]interface{}{"uri": "go-mcp-context:///unknown/1"}, -32602},
+		{"invalid library id", "resources/subscribe", map[string]interface{}{"uri": "go-mcp-context:///library/abc"}, -32602},
+		{"stateless subscribe", "resources/subscribe", map[string]interface{}{"uri": "go-mcp-context:///library/1"}, -32600},
+		{"stateless unsubscribe", "resources/unsubscribe", map[string]interface{}{"uri": "go-mcp-context:///docs/chunk/1/v1.0.0/routing"}, -32600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			writer := newMockResponseWriter()
+
+			req := &transport.RequestContext{
+				Transport: transport.TransportHTTP,
+				Method:    tt.method,
+				Params:    tt.params,
+				ID:        1,
+				GinCtx:    c,
+			}
+
+			if err := handler.ProcessRequest(req, writer); err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+
+			if len(writer.errors) != 1 {
+				t.Fatalf("Expected 1 error, got %d", len(writer.errors))
+			}
+			if writer.errors[0].Code != tt.wantCode {
+				t.Errorf("Expected error code %d, got %d", tt.wantCode, writer.errors[0].Code)
+			}
+		})
+	}
+}
+
+// Test_MCPHandler_CompletionComplete 测试 completion/complete 参数校验
+func Test_MCPHandler_CompletionComplete(t *testing.T) {
+	handler := service.NewMCPHandler()
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		wantCode int
+	}{
+		{"missing argument", map[string]interface{}{
+			"ref": map[string]interface{}{"type": "ref/prompt", "name": "explain-usage"},
+		}, -32602},
+		{"unsupported ref type", map[string]interface{}{
+			"ref":      map[string]interface{}{"type": "ref/tool", "name": "get-library-docs"},
+			"argument": map[string]interface{}{"name": "libraryId", "value": "1"},
+		}, -32602},
+		{"unknown resource template", map[string]interface{}{
+			"ref":      map[string]interface{}{"type": "ref/resource", "uri": "go-mcp-context:///unknown/{id}"},
+			"argument": map[string]interface{}{"name": "id", "value": ""},
+		}, -32602},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			writer := newMockResponseWriter()
+
+			req := &transport.RequestContext{
+				Transport: transport.TransportHTTP,
+				Method:    "completion/complete",
+				Params:    tt.params,
+				ID:        1,
+				GinCtx:    c,
+			}
+
+			if err := handler.ProcessRequest(req, writer); err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+
+			if len(writer.errors) != 1 {
+				t.Fatalf("Expected 1 error, got %d", len(writer.errors))
+			}
+			if writer.errors[0].Code != tt.wantCode {
+				t.Errorf("Expected error code %d, got %d", tt.wantCode, writer.errors[0].Code)
+			}
+		})
+	}
+}
+
 // Test_MCPHandler_ToolsCall_EdgeCases 测试 tools/call 边界情况
 func Test_MCPHandler_ToolsCall_EdgeCases(t *testing.T) {
 	handler := service.NewMCPHandler()
@@ -583,6 +830,83 @@ func Test_MCPHandler_ToolsCall_EdgeCases(t *testing.T) {
 	})
 }
 
+// Test_MCPHandler_ToolsCall_ToolErrors 测试工具执行失败以 isError 结果返回
+func Test_MCPHandler_ToolsCall_ToolErrors(t *testing.T) {
+	handler := service.NewMCPHandler()
+
+	libService := &service.LibraryService{}
+	lib, err := libService.Create(&request.LibraryCreate{
+		Name:        "test-tool-error-lib",
+		Description: "Test library for tool errors",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	defer libService.Delete(lib.ID)
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		wantText string
+	}{
+		{"unknown library", map[string]interface{}{
+			"libraryId": float64(999999),
+			"topic":     "routing",
+			"version":   "latest",
+		}, "search-libraries"},
+		{"unknown version", map[string]interface{}{
+			"libraryId": float64(lib.ID),
+			"topic":     "routing",
+			"version":   "v99.99.99",
+		}, lib.DefaultVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			writer := newMockResponseWriter()
+
+			req := &transport.RequestContext{
+				Transport: transport.TransportHTTP,
+				Method:    "tools/call",
+				Params: map[string]interface{}{
+					"name":      "get-library-docs",
+					"arguments": tt.args,
+				},
+				ID:     1,
+				GinCtx: c,
+			}
+
+			if err := handler.ProcessRequest(req, writer); err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+
+			if len(writer.errors) != 0 {
+				t.Fatalf("Expected no JSON-RPC error, got %+v", writer.errors[0])
+			}
+			if len(writer.responses) != 1 {
+				t.Fatalf("Expected 1 response, got %d", len(writer.responses))
+			}
+
+			result := writer.responses[0].Result.(map[string]interface{})
+			if result["isError"] != true {
+				t.Errorf("Expected isError true, got %v", result["isError"])
+			}
+			text := result["content"].([]map[string]interface{})[0]["text"].(string)
+			if !strings.Contains(text, tt.wantText) {
+				t.Errorf("Expected guidance to mention %q, got %q", tt.wantText, text)
+			}
+
+			// 中间件据此记录为失败调用
+			if _, exists := c.Get("mcp_tool_error"); !exists {
+				t.Error("Expected mcp_tool_error to be set")
+			}
+		})
+	}
+}
+
 // Test_MCPHandler_ResourceTemplatesList 测试 resources/templates/list
 func Test_MCPHandler_ResourceTemplatesList(t *testing.T) {
 	handler := service.NewMCPHandler()
@@ -0,0 +1,127 @@
+package test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/pkg/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test_MCP_ListLibrariesPage 测试 resources/list 游标分页与访问过滤
+func Test_MCP_ListLibrariesPage(t *testing.T) {
+	mcpService := service.NewMCPService()
+	libService := &service.LibraryService{}
+	owner := "00000000-0000-0000-0000-0000000000aa"
+
+	// 每页 1 条，便于验证翻页
+	pageSize := global.Config.MCP.PageSize
+	global.Config.MCP.PageSize = 1
+	defer func() { global.Config.MCP.PageSize = pageSize }()
+
+	for i := 0; i < 2; i++ {
+		lib, err := libService.Create(&request.LibraryCreate{
+			Name:      fmt.Sprintf("page-owned-lib-%d", i),
+			CreatedBy: owner,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create library: %v", err)
+		}
+		defer libService.Delete(lib.ID)
+	}
+
+	t.Run("page through owned libraries", func(t *testing.T) {
+		req := &request.MCPListResources{Access: service.AccessFilterOwned, UserID: owner}
+		var names []string
+		for page := 0; page < 5; page++ {
+			libraries, next, err := mcpService.ListLibrariesPage(context.Background(), req)
+			if err != nil {
+				t.Fatalf("ListLibrariesPage() error = %v", err)
+			}
+			for _, lib := range libraries {
+				names = append(names, lib.Name)
+			}
+			if next == "" {
+				break
+			}
+			// 后续页只传游标，访问过滤由游标携带
+			req = &request.MCPListResources{Cursor: next, UserID: owner}
+		}
+
+		if len(names) != 2 {
+			t.Errorf("Expected 2 owned libraries, got %v", names)
+		}
+	})
+
+	t.Run("cursor bound to caller", func(t *testing.T) {
+		_, next, err := mcpService.ListLibrariesPage(context.Background(), &request.MCPListResources{
+			Access: service.AccessFilterOwned,
+			UserID: owner,
+		})
+		if err != nil || next == "" {
+			t.Fatalf("Expected next cursor, got %q, err = %v", next, err)
+		}
+
+		_, _, err = mcpService.ListLibrariesPage(context.Background(), &request.MCPListResources{
+			Cursor: next,
+			UserID: "00000000-0000-0000-0000-0000000000bb",
+		})
+		if !errors.Is(err, service.ErrInvalidParams) {
+			t.Errorf("Expected ErrInvalidParams, got %v", err)
+		}
+	})
+
+	t.Run("tampered cursor", func(t *testing.T) {
+		_, _, err := mcpService.ListLibrariesPage(context.Background(), &request.MCPListResources{
+			Cursor: "eyJrIjoicmVzb3VyY2VzIiwiYSI6MX0.invalid",
+		})
+		if !errors.Is(err, service.ErrInvalidParams) {
+			t.Errorf("Expected ErrInvalidParams, got %v", err)
+		}
+	})
+}
+
+// Test_MCPHandler_ResourcesList_InvalidParams 测试 resources/list 与 templates/list 参数校验
+func Test_MCPHandler_ResourcesList_InvalidParams(t *testing.T) {
+	handler := service.NewMCPHandler()
+
+	tests := []struct {
+		name   string
+		method string
+		params map[string]interface{}
+	}{
+		{"invalid cursor", "resources/list", map[string]interface{}{"cursor": "not-a-cursor"}},
+		{"unknown access filter", "resources/list", map[string]interface{}{"filter": map[string]interface{}{"access": "everyone"}}},
+		{"invalid templates cursor", "resources/templates/list", map[string]interface{}{"cursor": "not-a-cursor"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			writer := newMockResponseWriter()
+
+			req := &transport.RequestContext{
+				Transport: transport.TransportHTTP,
+				Method:    tt.method,
+				Params:    tt.params,
+				ID:        1,
+				GinCtx:    c,
+			}
+
+			if err := handler.ProcessRequest(req, writer); err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+			if len(writer.errors) != 1 || writer.errors[0].Code != -32602 {
+				t.Errorf("Expected -32602 error, got %+v", writer.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package test_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport/streamable"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test_StreamableWriter_Progress 测试携带 progressToken 时切换为SSE并推送进度
+func Test_StreamableWriter_Progress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("progress token switches to SSE", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		writer := streamable.NewStreamableResponseWriter(c)
+		writer.SetRequestInfo("tools/call", map[string]interface{}{
+			"name":  "get-library-docs",
+			"_meta": map[string]interface{}{"progressToken": "tok-1"},
+		})
+
+		if err := writer.WriteNotification(response.NewMCPNotification("notifications/progress", map[string]interface{}{
+			"progressToken": "tok-1",
+			"progress":      1,
+		})); err != nil {
+			t.Fatalf("WriteNotification() error = %v", err)
+		}
+		if err := writer.WriteResponse(&response.MCPResponse{JSONRPC: "2.0", ID: 1, Result: map[string]interface{}{}}); err != nil {
+			t.Fatalf("WriteResponse() error = %v", err)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("Expected text/event-stream, got %s", ct)
+		}
+		body := w.Body.String()
+		progressAt := strings.Index(body, "notifications/progress")
+		resultAt := strings.Index(body, `"result"`)
+		if progressAt < 0 || resultAt < 0 || progressAt > resultAt {
+			t.Errorf("Expected progress event before result, got %q", body)
+		}
+	})
+
+	t.Run("no progress token returns JSON", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		writer := streamable.NewStreamableResponseWriter(c)
+		writer.SetRequestInfo("tools/call", map[string]interface{}{"name": "get-library-docs"})
+
+		writer.WriteNotification(response.NewMCPNotification("notifications/progress", nil))
+		if err := writer.WriteResponse(&response.MCPResponse{JSONRPC: "2.0", ID: 1, Result: map[string]interface{}{}}); err != nil {
+			t.Fatalf("WriteResponse() error = %v", err)
+		}
+
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("Expected application/json, got %s", ct)
+		}
+		if strings.Contains(w.Body.String(), "notifications/progress") {
+			t.Error("Expected progress notification to be dropped")
+		}
+	})
+}
@@ -1,10 +1,18 @@
 package test_test
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
 	"testing"
 
+	dbmodel "go-mcp-context/internal/model/database"
 	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/service"
+	"go-mcp-context/pkg/global"
+
+	"github.com/lib/pq"
 )
 
 // TestMCPSearchLibraries 测试 MCP 库搜索
@@ -356,6 +364,134 @@ func Test_MCP_GetLibraryDocs_Advanced(t *testing.T) {
 	})
 }
 
+// Test_MCP_GetLibraryDocs_CrossLibrary 测试省略 libraryId 时的跨库检索
+func Test_MCP_GetLibraryDocs_CrossLibrary(t *testing.T) {
+	mcpService := service.NewMCPService()
+	libService := &service.LibraryService{}
+
+	libA, err := libService.Create(&request.LibraryCreate{Name: "cross-lib-a", Description: "cross library search A"})
+	if err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	defer libService.Delete(libA.ID)
+	libB, err := libService.Create(&request.LibraryCreate{Name: "cross-lib-b", Description: "cross library search B"})
+	if err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	defer libService.Delete(libB.ID)
+
+	t.Run("subset of libraries", func(t *testing.T) {
+		result, err := mcpService.GetLibraryDocs(&request.MCPGetLibraryDocs{
+			LibraryIDs: []uint{libA.ID, libB.ID},
+			Topic:      "getting started",
+		})
+		if err != nil {
+			t.Logf("GetLibraryDocs() error = %v (expected if embedding unavailable)", err)
+			return
+		}
+		if result.LibraryID != 0 {
+			t.Errorf("Expected libraryId 0 for cross-library search, got %d", result.LibraryID)
+		}
+		for _, doc := range result.Documents {
+			if doc.LibraryID != libA.ID && doc.LibraryID != libB.ID {
+				t.Errorf("Expected documents only from the requested libraries, got libraryId %d", doc.LibraryID)
+			}
+			if doc.Library == "" {
+				t.Error("Expected document to be labeled with its library name")
+			}
+		}
+	})
+
+	t.Run("too many libraries", func(t *testing.T) {
+		ids := make([]uint, service.MaxCrossLibraries+1)
+		for i := range ids {
+			ids[i] = uint(i + 1)
+		}
+		_, err := mcpService.GetLibraryDocs(&request.MCPGetLibraryDocs{
+			LibraryIDs: ids,
+			Topic:      "getting started",
+		})
+		if !errors.Is(err, service.ErrInvalidParams) {
+			t.Errorf("Expected ErrInvalidParams, got %v", err)
+		}
+	})
+
+	t.Run("unknown library in subset", func(t *testing.T) {
+		_, err := mcpService.GetLibraryDocs(&request.MCPGetLibraryDocs{
+			LibraryIDs: []uint{libA.ID, 999999},
+			Topic:      "getting started",
+		})
+		if !errors.Is(err, service.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+// Test_MCP_ResolveDependencies 测试依赖清单解析为库与版本
+func Test_MCP_ResolveDependencies(t *testing.T) {
+	mcpService := service.NewMCPService()
+	libService := &service.LibraryService{}
+
+	lib, err := libService.Create(&request.LibraryCreate{Name: "resolve-deps-gin", Description: "gin web framework"})
+	if err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	defer libService.Delete(lib.ID)
+	global.DB.Model(&dbmodel.Library{}).Where("id = ?", lib.ID).Updates(map[string]interface{}{
+		"source_type":     "github",
+		"source_url":      "gin-gonic/gin",
+		"versions":        pq.StringArray{"v1.8.0", "v1.9.1", "v1.10.0"},
+		"default_version": "v1.10.0",
+	})
+
+	t.Run("go module matched by source url", func(t *testing.T) {
+		result, err := mcpService.ResolveDependencies(&request.MCPResolveDependencies{
+			Manifests: []request.MCPManifest{{
+				Filename: "go.mod",
+				Content:  "module example.com/app\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\texample.com/not-indexed-dependency v0.1.0\n)\n",
+			}},
+		})
+		if err != nil {
+			t.Fatalf("ResolveDependencies() error = %v", err)
+		}
+		if len(result.Dependencies) != 2 {
+			t.Fatalf("Expected 2 dependencies, got %d", len(result.Dependencies))
+		}
+
+		gin := result.Dependencies[0]
+		if gin.LibraryID != lib.ID || gin.MatchedBy != "source_url" {
+			t.Errorf("Expected gin matched to library %d by source_url, got %d by %q", lib.ID, gin.LibraryID, gin.MatchedBy)
+		}
+		if gin.Version != "v1.9.1" || gin.VersionMatch != "exact" {
+			t.Errorf("Expected exact version v1.9.1, got %s (%s)", gin.Version, gin.VersionMatch)
+		}
+		if result.Dependencies[1].LibraryID != 0 {
+			t.Errorf("Expected unmatched dependency, got libraryId %d", result.Dependencies[1].LibraryID)
+		}
+	})
+
+	t.Run("closest version", func(t *testing.T) {
+		result, err := mcpService.ResolveDependencies(&request.MCPResolveDependencies{
+			Manifests: []request.MCPManifest{{Filename: "go.mod", Content: "require github.com/gin-gonic/gin v1.9.0\n"}},
+		})
+		if err != nil {
+			t.Fatalf("ResolveDependencies() error = %v", err)
+		}
+		if dep := result.Dependencies[0]; dep.Version != "v1.9.1" || dep.VersionMatch != "closest" {
+			t.Errorf("Expected closest version v1.9.1, got %s (%s)", dep.Version, dep.VersionMatch)
+		}
+	})
+
+	t.Run("unsupported manifest", func(t *testing.T) {
+		_, err := mcpService.ResolveDependencies(&request.MCPResolveDependencies{
+			Manifests: []request.MCPManifest{{Filename: "Cargo.toml", Content: "[dependencies]"}},
+		})
+		if !errors.Is(err, service.ErrInvalidParams) {
+			t.Errorf("Expected ErrInvalidParams, got %v", err)
+		}
+	})
+}
+
 // TestMCPGetAllLibrariesAdvanced 测试获取所有库的高级场景
 func Test_MCP_GetAllLibraries_Advanced(t *testing.T) {
 	mcpService := service.NewMCPService()
@@ -459,3 +595,73 @@ func Test_MCP_SearchLibraries_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+// Test_MCP_Complete 测试 completion/complete 参数补全
+func Test_MCP_Complete(t *testing.T) {
+	mcpService := service.NewMCPService()
+	libService := &service.LibraryService{}
+	lib, err := libService.Create(&request.LibraryCreate{
+		Name:        "mcp-complete-lib",
+		Description: "test library for completion",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	defer libService.Delete(lib.ID)
+
+	docsTemplate := request.MCPCompletionRef{
+		Type: "ref/resource",
+		URI:  "go-mcp-context:///docs/chunk/{libraryId}/{version}/{topic}",
+	}
+
+	t.Run("complete library name", func(t *testing.T) {
+		result, err := mcpService.Complete(context.Background(), &request.MCPComplete{
+			Ref:           request.MCPCompletionRef{Type: "ref/prompt", Name: "explain-usage"},
+			ArgumentName:  "library",
+			ArgumentValue: "mcp-complete",
+		})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		if !slices.Contains(result.Completion.Values, "mcp-complete-lib") {
+			t.Errorf("Expected mcp-complete-lib in %v", result.Completion.Values)
+		}
+	})
+
+	t.Run("complete library id", func(t *testing.T) {
+		result, err := mcpService.Complete(context.Background(), &request.MCPComplete{
+			Ref:           docsTemplate,
+			ArgumentName:  "libraryId",
+			ArgumentValue: "mcp-complete",
+		})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		if !slices.Contains(result.Completion.Values, strconv.FormatUint(uint64(lib.ID), 10)) {
+			t.Errorf("Expected library id %d in %v", lib.ID, result.Completion.Values)
+		}
+	})
+
+	t.Run("version without library context", func(t *testing.T) {
+		result, err := mcpService.Complete(context.Background(), &request.MCPComplete{
+			Ref:          docsTemplate,
+			ArgumentName: "version",
+		})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		if len(result.Completion.Values) != 0 {
+			t.Errorf("Expected no values, got %v", result.Completion.Values)
+		}
+	})
+
+	t.Run("unknown resource template", func(t *testing.T) {
+		_, err := mcpService.Complete(context.Background(), &request.MCPComplete{
+			Ref:          request.MCPCompletionRef{Type: "ref/resource", URI: "go-mcp-context:///unknown/{id}"},
+			ArgumentName: "id",
+		})
+		if !errors.Is(err, service.ErrInvalidParams) {
+			t.Errorf("Expected ErrInvalidParams, got %v", err)
+		}
+	})
+}
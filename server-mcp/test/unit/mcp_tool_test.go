@@ -0,0 +1,141 @@
+package test_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/pkg/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+// echoTool 测试用工具
+type echoTool struct{}
+
+func (t *echoTool) Name() string        { return "test-echo" }
+func (t *echoTool) Description() string { return "Echo the message back" }
+
+func (t *echoTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"message": map[string]interface{}{"type": "string", "minLength": 1},
+			"times":   map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 3},
+			"style":   map[string]interface{}{"type": "string", "enum": []string{"plain", "loud"}},
+		},
+		"required": []string{"message"},
+	}
+}
+
+func (t *echoTool) OutputSchema() map[string]interface{} { return nil }
+
+func (t *echoTool) Call(ctx context.Context, args map[string]interface{}) (*service.MCPToolResult, error) {
+	return &service.MCPToolResult{Text: args["message"].(string), ResultCount: 1}, nil
+}
+
+// Test_MCPToolRegistry 测试工具注册表驱动 tools/list 与 tools/call
+func Test_MCPToolRegistry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := service.NewMCPHandler()
+
+	service.MCPTools.Register(&echoTool{})
+	defer service.MCPTools.Unregister("test-echo")
+
+	call := func(method string, params map[string]interface{}) *mockResponseWriter {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		writer := newMockResponseWriter()
+		req := &transport.RequestContext{
+			Transport: transport.TransportHTTP,
+			Method:    method,
+			Params:    params,
+			ID:        1,
+			GinCtx:    c,
+		}
+		if err := handler.ProcessRequest(req, writer); err != nil {
+			t.Fatalf("ProcessRequest() error = %v", err)
+		}
+		return writer
+	}
+
+	listed := func() bool {
+		writer := call("tools/list", map[string]interface{}{})
+		tools := writer.responses[0].Result.(map[string]interface{})["tools"].([]map[string]interface{})
+		for _, tool := range tools {
+			if tool["name"] == "test-echo" {
+				if _, ok := tool["outputSchema"]; ok {
+					t.Error("Expected no outputSchema for tool without structured output")
+				}
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("registered tool is listed and callable", func(t *testing.T) {
+		if !listed() {
+			t.Fatal("Expected test-echo in tools/list")
+		}
+
+		writer := call("tools/call", map[string]interface{}{
+			"name":      "test-echo",
+			"arguments": map[string]interface{}{"message": "hello", "times": float64(2)},
+		})
+		if len(writer.responses) != 1 {
+			t.Fatalf("Expected 1 response, got errors %+v", writer.errors)
+		}
+		result := writer.responses[0].Result.(map[string]interface{})
+		if text := result["content"].([]map[string]interface{})[0]["text"]; text != "hello" {
+			t.Errorf("Expected echoed text, got %v", text)
+		}
+		if _, ok := result["structuredContent"]; ok {
+			t.Error("Expected no structuredContent")
+		}
+	})
+
+	t.Run("arguments are validated against inputSchema", func(t *testing.T) {
+		invalid := []map[string]interface{}{
+			{},                                       // 缺少必填参数
+			{"message": float64(1)},                  // 类型错误
+			{"message": ""},                          // minLength
+			{"message": "hi", "times": float64(1.5)}, // 非整数
+			{"message": "hi", "times": float64(5)},   // maximum
+			{"message": "hi", "style": "quiet"},      // enum
+		}
+		for _, args := range invalid {
+			writer := call("tools/call", map[string]interface{}{"name": "test-echo", "arguments": args})
+			if len(writer.errors) != 1 || writer.errors[0].Code != -32602 {
+				t.Errorf("Expected -32602 for %v, got responses=%d errors=%+v", args, len(writer.responses), writer.errors)
+			}
+		}
+	})
+
+	t.Run("disabled tool is hidden", func(t *testing.T) {
+		disabled := global.Config.MCP.DisabledTools
+		global.Config.MCP.DisabledTools = []string{"test-echo"}
+		defer func() { global.Config.MCP.DisabledTools = disabled }()
+
+		if listed() {
+			t.Error("Expected disabled tool to be hidden from tools/list")
+		}
+		writer := call("tools/call", map[string]interface{}{
+			"name":      "test-echo",
+			"arguments": map[string]interface{}{"message": "hello"},
+		})
+		if len(writer.errors) != 1 || writer.errors[0].Code != -32602 {
+			t.Errorf("Expected unknown tool error, got %+v", writer.errors)
+		}
+	})
+
+	t.Run("registered tools for call logs", func(t *testing.T) {
+		// 已关闭的工具仍记录工具名，未注册的不记录
+		if !service.MCPTools.Has("get-library-docs") || !service.MCPTools.Has("test-echo") {
+			t.Error("Expected registered tools (including disabled ones) to be known")
+		}
+		if service.MCPTools.Has("no-such-tool") {
+			t.Error("Expected unknown tool not to be registered")
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package test_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-mcp-context/internal/middleware"
+	"go-mcp-context/internal/model/request"
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/router"
+	"go-mcp-context/internal/service"
+	"go-mcp-context/internal/transport"
+	"go-mcp-context/pkg/global"
+
+	dbmodel "go-mcp-context/internal/model/database"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// Test_Prompt_CRUD 测试提示模板增删改查
+func Test_Prompt_CRUD(t *testing.T) {
+	promptService := &service.PromptService{}
+
+	created, err := promptService.Create(&request.PromptCreate{
+		Name:     "test-crud-prompt",
+		Title:    "Test prompt",
+		Template: "Hello {{name}}",
+		Arguments: []dbmodel.PromptArgument{
+			{Name: "name", Required: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !created.Enabled {
+		t.Error("Expected prompt enabled by default")
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, err := promptService.Create(&request.PromptCreate{Name: "test-crud-prompt", Template: "x"})
+		if !errors.Is(err, service.ErrAlreadyExists) {
+			t.Errorf("Expected ErrAlreadyExists, got %v", err)
+		}
+	})
+
+	t.Run("update and disable", func(t *testing.T) {
+		disabled := false
+		updated, err := promptService.Update(created.ID, &request.PromptUpdate{
+			Title:    "Updated",
+			Template: "Hi {{name}}",
+			Enabled:  &disabled,
+		})
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if updated.Title != "Updated" || updated.Enabled {
+			t.Errorf("Unexpected update result: %+v", updated)
+		}
+
+		prompts, err := promptService.ListMCPPrompts()
+		if err != nil {
+			t.Fatalf("ListMCPPrompts() error = %v", err)
+		}
+		for _, p := range prompts {
+			if p.Name == "test-crud-prompt" {
+				t.Error("Disabled prompt should not be listed")
+			}
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := promptService.Delete(created.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if err := promptService.Delete(created.ID); !errors.Is(err, service.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+// Test_MCPHandler_Prompts 测试 prompts/list 与 prompts/get
+func Test_MCPHandler_Prompts(t *testing.T) {
+	handler := service.NewMCPHandler()
+	gin.SetMode(gin.TestMode)
+
+	newRequest := func(method string, params map[string]interface{}) *transport.RequestContext {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		return &transport.RequestContext{
+			Transport: transport.TransportHTTP,
+			Method:    method,
+			Params:    params,
+			ID:        1,
+			GinCtx:    c,
+		}
+	}
+
+	t.Run("list includes builtin prompts", func(t *testing.T) {
+		writer := newMockResponseWriter()
+		if err := handler.ProcessRequest(newRequest("prompts/list", map[string]interface{}{}), writer); err != nil {
+			t.Fatalf("ProcessRequest() error = %v", err)
+		}
+		if len(writer.responses) != 1 {
+			t.Fatalf("Expected 1 response, got %d", len(writer.responses))
+		}
+
+		result := writer.responses[0].Result.(map[string]interface{})
+		prompts := result["prompts"].([]response.MCPPrompt)
+		names := make(map[string]bool)
+		for _, p := range prompts {
+			names[p.Name] = true
+		}
+		for _, name := range []string{"explain-usage", "migrate-version", "review-snippet"} {
+			if !names[name] {
+				t.Errorf("Expected builtin prompt %s", name)
+			}
+		}
+	})
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+	}{
+		{"missing name", map[string]interface{}{}},
+		{"unknown prompt", map[string]interface{}{"name": "no-such-prompt"}},
+		{"missing required argument", map[string]interface{}{"name": "explain-usage", "arguments": map[string]interface{}{"library": "gin"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := newMockResponseWriter()
+			if err := handler.ProcessRequest(newRequest("prompts/get", tt.params), writer); err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+			if len(writer.errors) != 1 || writer.errors[0].Code != -32602 {
+				t.Errorf("Expected -32602 error, got %+v", writer.errors)
+			}
+		})
+	}
+}
+
+// Test_Prompt_AdminRoutes 测试提示模板增删改需要管理员权限
+func Test_Prompt_AdminRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	adminUUID := uuid.Must(uuid.NewV4())
+	oldAdmins := global.Config.System.AdminUUIDs
+	defer func() { global.Config.System.AdminUUIDs = oldAdmins }()
+	global.Config.System.AdminUUIDs = []string{adminUUID.String()}
+
+	call := func(userUUID uuid.UUID, method, path string) int {
+		r := gin.New()
+		group := r.Group("/api/v1", func(c *gin.Context) {
+			c.Set("user_uuid", userUUID)
+		})
+		router.RouterGroupApp.InitPromptRouter(group)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	for _, tt := range []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/prompts"},
+		{http.MethodPut, "/api/v1/prompts/999999999"},
+		{http.MethodDelete, "/api/v1/prompts/999999999"},
+	} {
+		if code := call(uuid.Must(uuid.NewV4()), tt.method, tt.path); code != http.StatusForbidden {
+			t.Errorf("%s %s by non-admin: status = %d, want 403", tt.method, tt.path, code)
+		}
+		if code := call(adminUUID, tt.method, tt.path); code == http.StatusForbidden {
+			t.Errorf("%s %s by admin: status = 403", tt.method, tt.path)
+		}
+	}
+
+	if !middleware.IsAdmin(adminUUID.String()) {
+		t.Error("Expected configured user to be admin")
+	}
+	if middleware.IsAdmin("") {
+		t.Error("Expected empty user not to be admin")
+	}
+}
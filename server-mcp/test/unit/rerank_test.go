@@ -0,0 +1,161 @@
+package test_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-mcp-context/pkg/llm"
+	"go-mcp-context/pkg/rerank"
+)
+
+// fakeRerankLLM 返回固定回复的 LLM
+type fakeRerankLLM struct {
+	reply string
+	err   error
+}
+
+func (f *fakeRerankLLM) Enrich(ctx context.Context, input llm.EnrichInput) (*llm.EnrichOutput, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeRerankLLM) Chat(ctx context.Context, prompt string) (string, error) {
+	return f.reply, f.err
+}
+
+func (f *fakeRerankLLM) GenerateLibraryTitle(ctx context.Context, repoName, description string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Test_Rerank_HTTP 测试 HTTP cross-encoder 重排序（Cohere/Jina 与 TEI 格式）
+func Test_Rerank_HTTP(t *testing.T) {
+	documents := []string{"install gin", "gin middleware", "routing groups"}
+
+	t.Run("cohere format", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer test-key" {
+				t.Errorf("Expected bearer API key, got %q", r.Header.Get("Authorization"))
+			}
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["model"] != "test-model" || body["query"] != "middleware" {
+				t.Errorf("Unexpected request body: %v", body)
+			}
+			if docs, _ := body["documents"].([]interface{}); len(docs) != len(documents) {
+				t.Errorf("Expected %d documents, got %v", len(documents), body["documents"])
+			}
+			w.Write([]byte(`{"results":[{"index":1,"relevance_score":0.92},{"index":2,"relevance_score":0.15},{"index":0,"relevance_score":0.4},{"index":7,"relevance_score":0.99}]}`))
+		}))
+		defer server.Close()
+
+		reranker := rerank.NewHTTPReranker(rerank.HTTPConfig{Endpoint: server.URL, APIKey: "test-key", Model: "test-model"})
+		results, err := reranker.Rerank(context.Background(), "middleware", documents)
+		if err != nil {
+			t.Fatalf("Rerank() error = %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results (out-of-range index dropped), got %v", results)
+		}
+		if results[0].Index != 1 || results[1].Index != 0 || results[2].Index != 2 {
+			t.Errorf("Expected order [1 0 2], got %v", results)
+		}
+	})
+
+	t.Run("tei format", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if texts, _ := body["texts"].([]interface{}); len(texts) != len(documents) {
+				t.Errorf("Expected texts in TEI request, got %v", body)
+			}
+			w.Write([]byte(`[{"index":2,"score":0.8},{"index":0,"score":0.1}]`))
+		}))
+		defer server.Close()
+
+		reranker := rerank.NewHTTPReranker(rerank.HTTPConfig{Endpoint: server.URL, Format: rerank.FormatTEI})
+		results, err := reranker.Rerank(context.Background(), "groups", documents)
+		if err != nil {
+			t.Fatalf("Rerank() error = %v", err)
+		}
+		if len(results) != 2 || results[0].Index != 2 {
+			t.Errorf("Expected index 2 first, got %v", results)
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		reranker := rerank.NewHTTPReranker(rerank.HTTPConfig{Endpoint: server.URL})
+		if _, err := reranker.Rerank(context.Background(), "q", documents); err == nil {
+			t.Error("Expected error for non-200 status")
+		}
+	})
+
+	t.Run("context timeout", func(t *testing.T) {
+		block := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-block
+		}))
+		defer server.Close()
+		defer close(block)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reranker := rerank.NewHTTPReranker(rerank.HTTPConfig{Endpoint: server.URL})
+		if _, err := reranker.Rerank(ctx, "q", documents); err == nil {
+			t.Error("Expected error for cancelled context")
+		}
+	})
+}
+
+// Test_Rerank_LLM 测试基于 LLM 的重排序
+func Test_Rerank_LLM(t *testing.T) {
+	documents := []string{"install gin", "gin middleware", "routing groups"}
+
+	t.Run("scores are normalized and sorted", func(t *testing.T) {
+		reranker := rerank.NewLLMReranker(&fakeRerankLLM{
+			reply: "Here are the scores:\n" + `{"scores":[{"index":0,"score":3},{"index":1,"score":9},{"index":2,"score":12},{"index":1,"score":1}]}`,
+		})
+		results, err := reranker.Rerank(context.Background(), "middleware", documents)
+		if err != nil {
+			t.Fatalf("Rerank() error = %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results (duplicate index dropped), got %v", results)
+		}
+		if results[0].Index != 2 || results[0].Score != 1 {
+			t.Errorf("Expected index 2 with score clamped to 1, got %v", results[0])
+		}
+		if results[1].Index != 1 || results[1].Score != 0.9 {
+			t.Errorf("Expected index 1 with score 0.9, got %v", results[1])
+		}
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		reranker := rerank.NewLLMReranker(&fakeRerankLLM{reply: "no idea"})
+		if _, err := reranker.Rerank(context.Background(), "q", documents); err == nil {
+			t.Error("Expected error for response without JSON")
+		}
+	})
+
+	t.Run("llm error", func(t *testing.T) {
+		reranker := rerank.NewLLMReranker(&fakeRerankLLM{err: errors.New("rate limited")})
+		if _, err := reranker.Rerank(context.Background(), "q", documents); err == nil {
+			t.Error("Expected error when LLM fails")
+		}
+	})
+}
+
+// Test_Rerank_Noop 测试不做重排序
+func Test_Rerank_Noop(t *testing.T) {
+	results, err := rerank.NewNoopReranker().Rerank(context.Background(), "q", []string{"a", "b"})
+	if err != nil || len(results) != 0 {
+		t.Errorf("Expected no results, got %v err=%v", results, err)
+	}
+}
@@ -1,11 +1,14 @@
 package test_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"go-mcp-context/internal/model/request"
 	"go-mcp-context/internal/service"
+	"go-mcp-context/pkg/global"
 )
 
 // Test_Search_InvalidateLibraryCache 测试缓存失效
@@ -1368,6 +1371,121 @@ func Test_Search_SearchDocuments_Pagination(t *testing.T) {
 	})
 }
 
+// Test_Search_SearchDocuments_TokenBudget 测试按 token 预算分页
+func Test_Search_SearchDocuments_TokenBudget(t *testing.T) {
+	searchService := &service.SearchService{}
+	libService := &service.LibraryService{}
+
+	lib, _ := libService.Create(&request.LibraryCreate{
+		Name:        "token-budget-lib",
+		Description: "test token budget",
+	})
+
+	for _, budget := range []int{100, 500, 5000} {
+		t.Run(fmt.Sprintf("budget %d", budget), func(t *testing.T) {
+			result, err := searchService.SearchDocuments(&request.Search{
+				LibraryID:   lib.ID,
+				Version:     lib.DefaultVersion,
+				Query:       "test",
+				Page:        1,
+				TokenBudget: budget,
+			})
+			if err != nil {
+				t.Logf("SearchDocuments(budget=%d) error = %v", budget, err)
+				return
+			}
+
+			sum := 0
+			for i, item := range result.Results {
+				sum += item.Tokens
+				// 只有最后一个片段可能被截断
+				if item.Truncated && i != len(result.Results)-1 {
+					t.Errorf("Expected only the last result to be truncated, got result %d", i)
+				}
+			}
+			if result.TokensUsed != sum {
+				t.Errorf("Expected tokensUsed %d, got %d", sum, result.TokensUsed)
+			}
+			if result.TokensUsed > budget {
+				t.Errorf("Expected tokensUsed <= %d, got %d", budget, result.TokensUsed)
+			}
+			if result.HasMore && len(result.Results) == 0 {
+				t.Error("Expected at least one result when more pages are available")
+			}
+		})
+	}
+}
+
+// Test_Search_SearchDocuments_QueryExpansion 测试 LLM 查询扩展（子查询 + HyDE）
+func Test_Search_SearchDocuments_QueryExpansion(t *testing.T) {
+	searchService := &service.SearchService{}
+	libService := &service.LibraryService{}
+
+	lib, _ := libService.Create(&request.LibraryCreate{
+		Name:        "query-expansion-lib",
+		Description: "test query expansion",
+	})
+
+	oldLLM, oldSearch := global.LLM, global.Config.Search
+	defer func() {
+		global.LLM = oldLLM
+		global.Config.Search = oldSearch
+	}()
+	global.Config.Search.QueryExpansion = service.QueryExpansionBoth
+
+	tests := []struct {
+		name    string
+		topic   string
+		llm     *fakeRerankLLM
+		applied bool
+	}{
+		{"expansion applied", "ctx cancel", &fakeRerankLLM{reply: `{"queries":["context cancellation","cancel a request context"],"passage":"Use context.WithCancel to ..."}`}, true},
+		{"llm failure falls back to topic", "auth fallback", &fakeRerankLLM{err: errors.New("llm unavailable")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			global.LLM = tt.llm
+			result, err := searchService.SearchDocuments(&request.Search{
+				LibraryID: lib.ID,
+				Version:   lib.DefaultVersion,
+				Query:     tt.topic,
+				Page:      1,
+			})
+			if err != nil {
+				t.Logf("SearchDocuments(%s) error = %v", tt.topic, err)
+				return
+			}
+			if len(result.QueryExpansion) != 1 {
+				t.Fatalf("Expected expansion info for one topic, got %+v", result.QueryExpansion)
+			}
+			expansion := result.QueryExpansion[0]
+			if expansion.Topic != tt.topic || expansion.Applied != tt.applied {
+				t.Errorf("Expected topic %q applied=%v, got %+v", tt.topic, tt.applied, expansion)
+			}
+			if tt.applied && (len(expansion.Queries) != 2 || !expansion.HyDE) {
+				t.Errorf("Expected 2 expanded queries and HyDE, got %+v", expansion)
+			}
+		})
+	}
+
+	t.Run("expansion disabled", func(t *testing.T) {
+		global.Config.Search.QueryExpansion = service.QueryExpansionOff
+		result, err := searchService.SearchDocuments(&request.Search{
+			LibraryID: lib.ID,
+			Version:   lib.DefaultVersion,
+			Query:     "ctx cancel",
+			Page:      1,
+		})
+		if err != nil {
+			t.Logf("SearchDocuments error = %v", err)
+			return
+		}
+		if len(result.QueryExpansion) != 0 {
+			t.Errorf("Expected no expansion info when disabled, got %+v", result.QueryExpansion)
+		}
+	})
+}
+
 // Test_Search_SearchDocuments_MultiTopic 测试多主题搜索
 func Test_Search_SearchDocuments_MultiTopic(t *testing.T) {
 	searchService := &service.SearchService{}
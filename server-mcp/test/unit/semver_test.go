@@ -0,0 +1,70 @@
+package test_test
+
+import (
+	"testing"
+
+	"go-mcp-context/pkg/utils"
+)
+
+// Test_SemverRange 测试语义化版本范围匹配
+func Test_SemverRange(t *testing.T) {
+	tests := []struct {
+		expr    string
+		version string
+		want    bool
+	}{
+		{"^1.9", "1.9.0", true},
+		{"^1.9", "v1.12.3", true},
+		{"^1.9", "2.0.0", false},
+		{"^1.9", "1.8.9", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"~1.9.2", "1.9.5", true},
+		{"~1.9.2", "1.10.0", false},
+		{"1.x", "1.0.0", true},
+		{"1.x", "2.0.0", false},
+		{"1.9.*", "1.9.7", true},
+		{"1.9.0", "v1.9.0", true},
+		{">=1.2 <2", "1.5.0", true},
+		{">= 1.2 < 2", "2.0.0", false},
+		{">1.2", "1.2.9", false},
+		{"<=1.2", "1.2.9", true},
+		{"1.x || 3.x", "3.1.0", true},
+		{"*", "5.0.0", true},
+		{"^1.9", "1.10.0-beta.1", false}, // 预发布版本不参与普通范围匹配
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr+" "+tt.version, func(t *testing.T) {
+			v, ok := utils.ParseSemver(tt.version)
+			if !ok {
+				t.Fatalf("ParseSemver(%q) failed", tt.version)
+			}
+			got, valid := utils.MatchSemverRange(tt.expr, v)
+			if !valid {
+				t.Fatalf("MatchSemverRange(%q) reported invalid range", tt.expr)
+			}
+			if got != tt.want {
+				t.Errorf("MatchSemverRange(%q, %q) = %v, want %v", tt.expr, tt.version, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid range", func(t *testing.T) {
+		if _, valid := utils.MatchSemverRange("latest", utils.Semver{Major: 1}); valid {
+			t.Error("Expected 'latest' to be an invalid range")
+		}
+	})
+
+	t.Run("prerelease ordering", func(t *testing.T) {
+		beta, _ := utils.ParseSemver("1.0.0-beta.2")
+		beta10, _ := utils.ParseSemver("1.0.0-beta.10")
+		release, _ := utils.ParseSemver("1.0.0")
+		if utils.CompareSemver(beta, beta10) >= 0 {
+			t.Error("Expected beta.2 < beta.10")
+		}
+		if utils.CompareSemver(beta10, release) >= 0 {
+			t.Error("Expected prerelease < release")
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package test_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-mcp-context/internal/transport/session"
+	"go-mcp-context/pkg/global"
+)
+
+// Test_SessionStore_ResourceSubscriptions 测试资源订阅随会话终止、SSE 断开清理
+func Test_SessionStore_ResourceSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	store := session.NewStore(global.Redis)
+	const libraryID = 424242
+
+	sess, err := store.Create(ctx, "subs-user")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := store.AddResourceSubscription(ctx, sess.ID, libraryID, "go-mcp-context:///library/424242"); err != nil {
+		t.Fatalf("AddResourceSubscription() error = %v", err)
+	}
+	if err := store.MarkSSEOnline(ctx, "subs-sse", "subs-user"); err != nil {
+		t.Fatalf("MarkSSEOnline() error = %v", err)
+	}
+	if err := store.AddResourceSubscription(ctx, "subs-sse", libraryID, "go-mcp-context:///docs/chunk/424242/v1/routing"); err != nil {
+		t.Fatalf("AddResourceSubscription() error = %v", err)
+	}
+
+	subs, err := store.ResourceSubscriptions(ctx, libraryID)
+	if err != nil || len(subs) != 2 {
+		t.Fatalf("ResourceSubscriptions() = %v, %v, want 2 subscriptions", subs, err)
+	}
+	for _, id := range []string{sess.ID, "subs-sse"} {
+		if alive, err := store.Alive(ctx, id); err != nil || !alive {
+			t.Errorf("Alive(%s) = %v, %v, want true", id, alive, err)
+		}
+	}
+
+	if owner, err := store.SSEOwner(ctx, "subs-sse"); err != nil || owner != "subs-user" {
+		t.Errorf("SSEOwner() = %q, %v, want subs-user", owner, err)
+	}
+
+	t.Run("delete session", func(t *testing.T) {
+		if err := store.Delete(ctx, sess.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if alive, _ := store.Alive(ctx, sess.ID); alive {
+			t.Error("Expected deleted session not alive")
+		}
+		subs, _ := store.ResourceSubscriptions(ctx, libraryID)
+		if len(subs) != 1 || subs[0].SessionID != "subs-sse" {
+			t.Errorf("Expected only SSE subscription left, got %v", subs)
+		}
+	})
+
+	t.Run("sse offline", func(t *testing.T) {
+		if err := store.MarkSSEOffline(ctx, "subs-sse"); err != nil {
+			t.Fatalf("MarkSSEOffline() error = %v", err)
+		}
+		if alive, _ := store.Alive(ctx, "subs-sse"); alive {
+			t.Error("Expected offline SSE session not alive")
+		}
+		if _, err := store.SSEOwner(ctx, "subs-sse"); !errors.Is(err, session.ErrSessionNotFound) {
+			t.Errorf("SSEOwner() error = %v, want ErrSessionNotFound", err)
+		}
+		if subs, _ := store.ResourceSubscriptions(ctx, libraryID); len(subs) != 0 {
+			t.Errorf("Expected no subscriptions, got %v", subs)
+		}
+	})
+}
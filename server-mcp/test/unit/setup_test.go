@@ -105,6 +105,9 @@ func setupTestEnvironment() {
 	// 8. 初始化 LLM 服务（使用真实的 OpenAI API）
 	initialize.InitLLM()
 
+	// 9. 初始化检索重排序服务（按配置，默认不重排序）
+	initialize.InitReranker()
+
 	fmt.Println("✅ Test environment initialized")
 }
 
@@ -188,6 +191,7 @@ func CleanupTestData() {
 		"statistics",
 		"api_keys",
 		"search_cache",
+		"prompt_templates",
 		"document_chunks",
 		"document_uploads",
 		"libraries",
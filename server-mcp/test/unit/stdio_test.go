@@ -0,0 +1,51 @@
+package test_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go-mcp-context/internal/model/response"
+	"go-mcp-context/internal/transport/stdio"
+)
+
+// Test_StdioTransport 测试stdio逐行读取与写入
+func Test_StdioTransport(t *testing.T) {
+	t.Run("read messages line by line", func(t *testing.T) {
+		in := strings.NewReader("{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"ping\"}\n\n  \n{\"jsonrpc\":\"2.0\",\"method\":\"notifications/initialized\"}")
+
+		var lines []string
+		if err := stdio.ReadMessages(in, func(line []byte) {
+			lines = append(lines, string(line))
+		}); err != nil {
+			t.Fatalf("ReadMessages() error = %v", err)
+		}
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 messages (blank lines skipped, last line without newline kept), got %d", len(lines))
+		}
+	})
+
+	t.Run("write one message per line", func(t *testing.T) {
+		var out bytes.Buffer
+		writer := stdio.NewWriter(&out)
+
+		writer.WriteResponse(&response.MCPResponse{JSONRPC: "2.0", ID: 1, Result: map[string]interface{}{"text": "a\nb"}})
+		writer.WriteNotification(response.NewMCPNotification("notifications/progress", map[string]interface{}{"progress": 1}))
+		writer.WriteError(&response.MCPError{Code: -32601, Message: "Method not found"}, 2)
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 lines, got %d: %q", len(lines), out.String())
+		}
+		for _, line := range lines {
+			var msg map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				t.Errorf("Line is not valid JSON: %s", line)
+			}
+			if msg["jsonrpc"] != "2.0" {
+				t.Errorf("Expected jsonrpc 2.0, got %v", msg["jsonrpc"])
+			}
+		}
+	})
+}